@@ -8,38 +8,71 @@ import (
 
 func cpuid(eax, ecx uint32) (a, b, c, d uint32)
 
+// CPUIDWithMode resolves one leaf/subleaf either from a captured Data
+// snapshot (offline) or the live CPUID instruction, the offline/filename
+// pair every exported Get*/Is* helper in this package threads through. It
+// delegates to the Source abstraction (see sourceFromMode/FileSource) so
+// the offline path is the same JSON replay CaptureData/DataFromFile use
+// elsewhere, rather than a second ad-hoc file format.
+func CPUIDWithMode(leaf, subleaf uint32, offline bool, filename string) (a, b, c, d uint32) {
+	src, err := sourceFromMode(offline, filename)
+	if err != nil {
+		return 0, 0, 0, 0
+	}
+	return src.CPUID(leaf, subleaf)
+}
+
 // GetMaxFunctions returns the maximum standard and extended function values supported by the CPU.
-func GetMaxFunctions() (uint32, uint32) {
-	a, _, _, _ := cpuid(0, 0)
+func GetMaxFunctions(offline bool, filename string) (uint32, uint32) {
+	a, _, _, _ := CPUIDWithMode(0, 0, offline, filename)
 	maxFunc := a
 
-	a, _, _, _ = cpuid(0x80000000, 0)
+	a, _, _, _ = CPUIDWithMode(0x80000000, 0, offline, filename)
 	maxExtFunc := a
 
 	return maxFunc, maxExtFunc
 }
 
-// GetIntelHybrid returns information about hybrid CPUs for Intel processors.
-func GetIntelHybrid() IntelHybridInfo {
-	a, _, _, _ := cpuid(0x1A, 0)
+// IntelHybridInfo describes whether the CPU is a hybrid part (a mix of
+// P-cores and E-cores, e.g. Alder Lake and later) per CPUID.1AH:EAX, and if
+// so which kind of core the calling logical CPU currently is.
+type IntelHybridInfo struct {
+	HybridCPU     bool
+	NativeModelID uint32
+	CoreType      uint32
+	CoreTypeName  string
+}
 
-	if (a & 1) == 0 {
+// GetIntelHybrid returns information about hybrid CPUs for Intel
+// processors, read live or replayed from a captured Data snapshot the same
+// way every other Get* helper in this package is. CPUID.1AH:EAX packs the
+// core type in bits[31:24] and the native model ID in bits[23:0] (see
+// intelCoreType in cpuid_hybrid_topology.go, which decodes the same leaf
+// for EnumerateLogicalCPUs); a CPU that isn't hybrid reports the whole leaf as
+// zero.
+func GetIntelHybrid(offline bool, filename string) IntelHybridInfo {
+	a, _, _, _ := CPUIDWithMode(0x1A, 0, offline, filename)
+
+	coreType := (a >> 24) & 0xFF
+	if coreType == 0 {
 		// Not hybrid
 		return IntelHybridInfo{HybridCPU: false}
 	}
 
 	hybridInfo := IntelHybridInfo{
 		HybridCPU:     true,
-		NativeModelID: (a >> 24) & 0xFF,
-		CoreType:      (a >> 16) & 0xFF,
+		NativeModelID: a & 0xFFFFFF,
+		CoreType:      coreType,
 	}
 
 	// Determine a human-readable core type
-	switch hybridInfo.CoreType {
-	case 1:
+	switch coreType {
+	case 0x40:
 		hybridInfo.CoreTypeName = "Performance core (P-core)"
-	case 2:
+	case 0x20:
 		hybridInfo.CoreTypeName = "Efficient core (E-core)"
+	case 0x30:
+		hybridInfo.CoreTypeName = "Low Power Efficient core (LP-E-core)"
 	default:
 		hybridInfo.CoreTypeName = "Unknown core type"
 	}
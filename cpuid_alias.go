@@ -0,0 +1,148 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// featureAliases maps every non-canonical spelling this package knows about
+// -- Linux /proc/cpuinfo flags, QEMU/libvirt lowercase "-cpu" flags, and a
+// few common Intel-manual/AMD-BKDG mnemonics -- to the canonical name used
+// as a Feature's name field in cpuFeaturesList. It is curated, not
+// exhaustive (libvirt's own sync_qemu_cpu_i386 table runs to thousands of
+// entries); new aliases are meant to be added here incrementally the way
+// cpumodels.Models/equivalence hints already grow.
+var featureAliases = map[string]string{
+	"sha-ni":            "SHA",
+	"sha_ni":            "SHA",
+	"xsaveopt":          "XSAVEOPT",
+	"avx512vnni":        "AVX512_VNNI",
+	"avx512_vnni":       "AVX512_VNNI",
+	"avx512bf16":        "AVX512_BF16",
+	"avx512_bf16":       "AVX512_BF16",
+	"avx_vnni":          "AVX_VNNI",
+	"avxvnni":           "AVX_VNNI",
+	"md-clear":          "MD_CLEAR",
+	"md_clear":          "MD_CLEAR",
+	"srbds-ctrl":        "SRBDS_CTRL",
+	"srbds_ctrl":        "SRBDS_CTRL",
+	"ibt":               "CET_IBT",
+	"cet_ibt":           "CET_IBT",
+	"shstk":             "CET_SS",
+	"cet_ss":            "CET_SS",
+	"waitpkg":           "WAITPKG",
+	"mwaitx":            "MWAITX",
+	"hfi":               "HFI_PERF",
+	"hfi_perf":          "HFI_PERF",
+	"hwp":               "HWP",
+	"lwp":               "LWP",
+	"fsrm":              "FSRM",
+	"uintr":             "UINTR",
+	"pconfig":           "PCONFIG",
+	"serialize":         "SERIALIZE",
+	"tsxldtrk":          "TSXLDTRK",
+	"amx_tile":          "AMX_TILE",
+	"amx-tile":          "AMX_TILE",
+	"amx_bf16":          "AMX_BF16",
+	"amx-bf16":          "AMX_BF16",
+	"amx_int8":          "AMX_INT8",
+	"amx-int8":          "AMX_INT8",
+	"la57":              "LA57",
+	"rdpid":             "RDPID",
+	"sgx":               "SGX",
+	"sgx_lc":            "SGX_LC",
+	"pku":               "PKU",
+	"ospke":             "OSPKE",
+	"la64":              "LAM",
+	"lam":               "LAM",
+	"cmpccxadd":         "CMPCCXADD",
+	"sm3":               "SM3",
+	"sm4":               "SM4",
+	"avx512fp16":        "AVX512_FP16",
+	"avx512_fp16":       "AVX512_FP16",
+	"hybrid":            "HYBRID",
+	"ibrs":              "IBRS_IBPB",
+	"ibpb":              "IBRS_IBPB",
+	"stibp":             "STIBP",
+	"ssbd":              "SSBD",
+	"l1d_flush":         "L1D_FLUSH",
+	"flush_l1d":         "L1D_FLUSH",
+	"arch_capabilities": "IA32_ARCH_CAPABILITIES",
+	"core_capabilities": "IA32_CORE_CAPABILITIES",
+	"rtm_always_abort":  "RTM_ALWAYS_ABORT",
+	"tsx_force_abort":   "TSX_FORCE_ABORT",
+}
+
+// Canonicalize resolves name -- an Intel manual mnemonic, AMD BKDG name,
+// Linux /proc/cpuinfo flag, QEMU/libvirt lowercase "-cpu" flag, or this
+// package's own internal Feature name -- to the single canonical name used
+// as a Feature's name field. Matching is case-insensitive; a "-" or "_"
+// spelling resolves only where featureAliases registers that exact form
+// (see normalizeFeatureKey). A name already in canonical form round-trips
+// unchanged, and an unrecognized name is returned uppercased as a
+// best-effort guess.
+func Canonicalize(name string) string {
+	key := normalizeFeatureKey(name)
+	if canon, ok := featureAliases[key]; ok {
+		return canon
+	}
+
+	upper := strings.ToUpper(name)
+	if _, ok := findFeatureNode(upper); ok {
+		return upper
+	}
+
+	return upper
+}
+
+// Aliases returns every known alternate spelling that resolves to name's
+// canonical form via Canonicalize, sorted by nothing in particular since
+// the set is small and curated.
+func Aliases(name string) []string {
+	canon := Canonicalize(name)
+
+	var aliases []string
+	for alias, mapped := range featureAliases {
+		if mapped == canon {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// Translate resolves from to the feature identifier toVendor's silicon
+// would use for the same concept: if from's canonical feature already
+// belongs to toVendor (or is vendor-common), it's returned as-is; otherwise
+// Translate walks the cross-vendor equivalence graph (see Equivalents) for
+// a feature tagged toVendor. It returns an error if from doesn't resolve to
+// a known feature, or no toVendor equivalent is known.
+func Translate(from, toVendor string) (string, error) {
+	canon := Canonicalize(from)
+
+	node, ok := findFeatureNode(canon)
+	if !ok {
+		return "", fmt.Errorf("cpuid: unknown feature identifier %q", from)
+	}
+	if node.feature.vendor == toVendor || node.feature.vendor == "common" {
+		return canon, nil
+	}
+
+	for _, eq := range Equivalents(canon) {
+		if eq.Vendor() == toVendor || eq.Vendor() == "common" {
+			return eq.Name(), nil
+		}
+	}
+
+	return "", fmt.Errorf("cpuid: no %s equivalent for %q", toVendor, canon)
+}
+
+// normalizeFeatureKey lowercases name so featureAliases can be keyed
+// case-insensitively; "-" and "_" are both used across the sources this
+// table draws from (QEMU flags use "-", /proc/cpuinfo uses neither, Intel
+// mnemonics use "_"), so both forms are registered explicitly in
+// featureAliases rather than folded here, to avoid false-positive merges
+// between unrelated flags that happen to differ only in punctuation.
+func normalizeFeatureKey(name string) string {
+	return strings.ToLower(name)
+}
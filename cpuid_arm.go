@@ -13,20 +13,22 @@ import (
 func cpuid(eax, ecx uint32) (a, b, c, d uint32)
 
 // GetMaxFunctions returns dummy values since ARM does not support CPUID enumeration.
-func GetMaxFunctions() (uint32, uint32) {
+func GetMaxFunctions(offline bool, filename string) (uint32, uint32) {
 	return 1, 0
 }
 
-// GetVendorID returns a formatted string with CPU identification info extracted from MIDR.
-// For ARMv7, the MIDR layout is:
+// GetVendorID returns a formatted string with CPU identification info
+// extracted from MIDR_EL1, read live or replayed from an ARMSnapshot
+// captured earlier (see CPUIDWithMode). For ARMv7, the MIDR layout is:
 //
 //	Bits [31:24] Implementer
 //	Bits [23:20] Variant
 //	Bits [19:16] Architecture
 //	Bits [15:4]  Part number
 //	Bits [3:0]   Revision
-func GetVendorID() string {
-	a, _, _, _ := cpuid(0, 0)
+func GetVendorID(offline bool, filename string) string {
+	midr := CPUIDWithMode(armRegMIDR, offline, filename)
+	a := uint32(midr)
 	implementer := (a >> 24) & 0xff
 	variant := (a >> 20) & 0xf
 	arch := (a >> 16) & 0xf
@@ -36,27 +38,23 @@ func GetVendorID() string {
 		implementer, variant, arch, part, revision)
 }
 
-// GetVendorName returns the vendor name based on the implementer field from MIDR.
-// It also includes a check to detect Apple silicon by inspecting the part number.
-// For example, many Apple M1 cores return a MIDR where:
-//
-//	Implementer == 0x41 (ARM Ltd.)
-//	Part number    == 0xD03
+// GetVendorName returns the vendor name based on the implementer field from
+// MIDR_EL1, read live or replayed the same way GetVendorID is. It used to
+// special-case 0x41/0xD03 as Apple silicon, but that's actually ARM Ltd.'s
+// own Cortex-A53 -- Apple cores report implementer 0x61, handled below
+// like any other implementer. See GetARMPartInfo for the full
+// (implementer, part) -> (vendor, part name, microarchitecture) resolution.
 //
 // In unknown cases, the function returns a string with the raw register values.
-func GetVendorName() string {
-	a, _, _, _ := cpuid(0, 0)
+func GetVendorName(offline bool, filename string) string {
+	midr := CPUIDWithMode(armRegMIDR, offline, filename)
+	a := uint32(midr)
 	implementer := (a >> 24) & 0xff
 	variant := (a >> 20) & 0xf
 	architecture := (a >> 16) & 0xf
 	part := (a >> 4) & 0xfff
 	revision := a & 0xf
 
-	// Check for Apple silicon.
-	if implementer == 0x41 && part == 0xD03 {
-		return "Apple"
-	}
-
 	switch implementer {
 	case 0x41:
 		return "ARM Ltd."
@@ -74,14 +72,99 @@ func GetVendorName() string {
 		return "Qualcomm"
 	case 0x56:
 		return "Marvell"
+	case 0x61:
+		return "Apple"
+	case 0x66:
+		return "Fujitsu"
 	default:
 		return fmt.Sprintf("Unknown (Implementer: 0x%X, Variant: 0x%X, Arch: 0x%X, Part: 0x%X, Rev: 0x%X)",
 			implementer, variant, architecture, part, revision)
 	}
 }
 
+// ARMPartInfo is the resolved (vendor, part name, microarchitecture) triple
+// for one MIDR implementer/part combination.
+type ARMPartInfo struct {
+	Vendor            string
+	PartName          string
+	Microarchitecture string
+}
+
+// armParts maps MIDR implementer -> part number -> ARMPartInfo, covering the
+// cores LLVM's ARM/AArch64 host detection (lib/Support/Host.cpp) recognises.
+// Apple's part numbers (implementer 0x61) come from MIDR_EL1 values observed
+// on A7 through M-series silicon; unlike ARM Ltd.'s parts they aren't
+// publicly documented by Apple itself.
+var armParts = map[uint32]map[uint32]ARMPartInfo{
+	0x41: { // ARM Ltd.
+		0xC05: {Vendor: "ARM Ltd.", PartName: "Cortex-A5", Microarchitecture: "Cortex-A5"},
+		0xC07: {Vendor: "ARM Ltd.", PartName: "Cortex-A7", Microarchitecture: "Cortex-A7"},
+		0xC08: {Vendor: "ARM Ltd.", PartName: "Cortex-A8", Microarchitecture: "Cortex-A8"},
+		0xC09: {Vendor: "ARM Ltd.", PartName: "Cortex-A9", Microarchitecture: "Cortex-A9"},
+		0xC0F: {Vendor: "ARM Ltd.", PartName: "Cortex-A15", Microarchitecture: "Cortex-A15"},
+		0xD03: {Vendor: "ARM Ltd.", PartName: "Cortex-A53", Microarchitecture: "Cortex-A53"},
+		0xD04: {Vendor: "ARM Ltd.", PartName: "Cortex-A35", Microarchitecture: "Cortex-A35"},
+		0xD05: {Vendor: "ARM Ltd.", PartName: "Cortex-A55", Microarchitecture: "Cortex-A55"},
+		0xD07: {Vendor: "ARM Ltd.", PartName: "Cortex-A57", Microarchitecture: "Cortex-A57"},
+		0xD08: {Vendor: "ARM Ltd.", PartName: "Cortex-A72", Microarchitecture: "Cortex-A72"},
+		0xD0B: {Vendor: "ARM Ltd.", PartName: "Cortex-A76", Microarchitecture: "Cortex-A76"},
+		0xD41: {Vendor: "ARM Ltd.", PartName: "Cortex-A78", Microarchitecture: "Cortex-A78"},
+		0xD44: {Vendor: "ARM Ltd.", PartName: "Cortex-X1", Microarchitecture: "Cortex-X1"},
+		0xD48: {Vendor: "ARM Ltd.", PartName: "Cortex-X2", Microarchitecture: "Cortex-X2"},
+		0xD0C: {Vendor: "ARM Ltd.", PartName: "Neoverse N1", Microarchitecture: "Neoverse N1"},
+		0xD40: {Vendor: "ARM Ltd.", PartName: "Neoverse V1", Microarchitecture: "Neoverse V1"},
+	},
+	0x42: { // Broadcom
+		0x516: {Vendor: "Broadcom", PartName: "Vulcan", Microarchitecture: "Vulcan"},
+	},
+	0x43: { // Cavium
+		0x0A1: {Vendor: "Cavium", PartName: "ThunderX", Microarchitecture: "ThunderX"},
+	},
+	0x51: { // Qualcomm
+		0x201: {Vendor: "Qualcomm", PartName: "Kryo", Microarchitecture: "Kryo"},
+		0x800: {Vendor: "Qualcomm", PartName: "Kryo", Microarchitecture: "Kryo (Cortex-A73 derived)"},
+		0xC00: {Vendor: "Qualcomm", PartName: "Falkor", Microarchitecture: "Falkor"},
+	},
+	0x66: { // Fujitsu
+		0x001: {Vendor: "Fujitsu", PartName: "A64FX", Microarchitecture: "A64FX"},
+	},
+	0x61: { // Apple
+		0x00: {Vendor: "Apple", PartName: "Swift", Microarchitecture: "Swift"},
+		0x01: {Vendor: "Apple", PartName: "Cyclone", Microarchitecture: "Cyclone"},
+		0x02: {Vendor: "Apple", PartName: "Typhoon", Microarchitecture: "Typhoon"},
+		0x04: {Vendor: "Apple", PartName: "Twister", Microarchitecture: "Twister"},
+		0x05: {Vendor: "Apple", PartName: "Hurricane", Microarchitecture: "Hurricane"},
+		0x06: {Vendor: "Apple", PartName: "Monsoon", Microarchitecture: "Monsoon"},
+		0x07: {Vendor: "Apple", PartName: "Mistral", Microarchitecture: "Mistral"},
+		0x08: {Vendor: "Apple", PartName: "Vortex", Microarchitecture: "Vortex"},
+		0x09: {Vendor: "Apple", PartName: "Tempest", Microarchitecture: "Tempest"},
+		0x0B: {Vendor: "Apple", PartName: "Lightning", Microarchitecture: "Lightning"},
+		0x0C: {Vendor: "Apple", PartName: "Thunder", Microarchitecture: "Thunder"},
+		0x22: {Vendor: "Apple", PartName: "Firestorm", Microarchitecture: "Firestorm"},
+		0x23: {Vendor: "Apple", PartName: "Icestorm", Microarchitecture: "Icestorm"},
+	},
+}
+
+// GetARMPartInfo resolves this CPU's MIDR implementer/part fields to the
+// structured (vendor, part name, microarchitecture) triple, and reports
+// whether the combination is in armParts. GetVendorName continues to
+// return just the vendor string for backward compatibility.
+func GetARMPartInfo(offline bool, filename string) (ARMPartInfo, bool) {
+	midr := CPUIDWithMode(armRegMIDR, offline, filename)
+	a := uint32(midr)
+	implementer := (a >> 24) & 0xff
+	part := (a >> 4) & 0xfff
+
+	parts, ok := armParts[implementer]
+	if !ok {
+		return ARMPartInfo{}, false
+	}
+	info, ok := parts[part]
+	return info, ok
+}
+
 // isARM returns true if the vendor name contains "ARM" or "Apple".
-func isARM() bool {
-	name := strings.ToUpper(GetVendorName())
+func isARM(offline bool, filename string) bool {
+	name := strings.ToUpper(GetVendorName(offline, filename))
 	return strings.Contains(name, "ARM") || strings.Contains(name, "APPLE")
 }
@@ -0,0 +1,115 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// ARM/ARM64 expose their feature bits through the OS rather than a CPUID
+// leaf/register the way x86 does, so GetSupportedFeatures and
+// IsFeatureSupported special-case two pseudo-categories instead of looking
+// them up in cpuFeaturesList: armCategoryHWCAP for Linux's
+// AT_HWCAP/AT_HWCAP2 auxv entries, and armCategoryApple for Darwin's
+// hw.optional.* sysctls. Both cover the standard ARMv8 features LLVM's
+// Host.cpp enumerates.
+const (
+	armCategoryHWCAP = "hwcap"
+	armCategoryApple = "apple"
+)
+
+// armHWCAPBit is one Linux AT_HWCAP/AT_HWCAP2 feature bit.
+type armHWCAPBit struct {
+	bit         uint
+	hwcap2      bool
+	description string
+}
+
+// armHWCAPFeatures maps canonical feature names to their Linux aarch64
+// HWCAP/HWCAP2 bit, per arch/arm64/include/uapi/asm/hwcap.h.
+var armHWCAPFeatures = map[string]armHWCAPBit{
+	"FP":      {bit: 0, description: "Floating point"},
+	"ASIMD":   {bit: 1, description: "Advanced SIMD (NEON)"},
+	"AES":     {bit: 3, description: "AES instructions"},
+	"PMULL":   {bit: 4, description: "Polynomial multiply long"},
+	"SHA1":    {bit: 5, description: "SHA1 instructions"},
+	"SHA2":    {bit: 6, description: "SHA2 (SHA256) instructions"},
+	"CRC32":   {bit: 7, description: "CRC32 instructions"},
+	"LSE":     {bit: 8, description: "Large System Extensions (atomics)"},
+	"FPHP":    {bit: 9, description: "Half-precision floating point"},
+	"ASIMDHP": {bit: 10, description: "Half-precision Advanced SIMD"},
+	"DCPOP":   {bit: 16, description: "Data cache clean to point of persistence"},
+	"SHA3":    {bit: 17, description: "SHA3 instructions"},
+	"SHA512":  {bit: 21, description: "SHA512 instructions"},
+	"SVE":     {bit: 22, description: "Scalable Vector Extension"},
+	"DIT":     {bit: 24, description: "Data Independent Timing"},
+	"FLAGM":   {bit: 27, description: "Flag manipulation instructions"},
+	"SSBS":    {bit: 28, description: "Speculative Store Bypass Safe"},
+	"SB":      {bit: 29, description: "Speculation Barrier instruction"},
+
+	"SVE2": {bit: 1, hwcap2: true, description: "Scalable Vector Extension 2"},
+	"I8MM": {bit: 13, hwcap2: true, description: "Int8 matrix multiply"},
+	"BF16": {bit: 14, hwcap2: true, description: "BFloat16 instructions"},
+	"DGH":  {bit: 15, hwcap2: true, description: "Data Gathering Hint"},
+	"RNG":  {bit: 16, hwcap2: true, description: "Random number generation instructions"},
+	"MTE":  {bit: 18, hwcap2: true, description: "Memory Tagging Extension"},
+}
+
+// appleFeatureSysctls maps canonical feature names to the Darwin
+// hw.optional.* sysctl that reports them.
+var appleFeatureSysctls = map[string]string{
+	"FP":      "hw.optional.floatingpoint",
+	"ASIMD":   "hw.optional.neon",
+	"AES":     "hw.optional.arm.FEAT_AES",
+	"PMULL":   "hw.optional.arm.FEAT_PMULL",
+	"SHA1":    "hw.optional.arm.FEAT_SHA1",
+	"SHA2":    "hw.optional.arm.FEAT_SHA256",
+	"CRC32":   "hw.optional.armv8_crc32",
+	"LSE":     "hw.optional.arm.FEAT_LSE",
+	"FPHP":    "hw.optional.arm.FEAT_FP16",
+	"ASIMDHP": "hw.optional.arm.FEAT_FP16",
+	"DCPOP":   "hw.optional.arm.FEAT_DPB",
+	"SHA3":    "hw.optional.arm.FEAT_SHA3",
+	"SHA512":  "hw.optional.arm.FEAT_SHA512",
+	"SVE":     "hw.optional.arm.FEAT_SVE",
+	"DIT":     "hw.optional.arm.FEAT_DIT",
+	"FLAGM":   "hw.optional.arm.FEAT_FlagM",
+	"SSBS":    "hw.optional.arm.FEAT_SSBS2",
+	"SB":      "hw.optional.arm.FEAT_SB",
+	"SVE2":    "hw.optional.arm.FEAT_SVE2",
+	"I8MM":    "hw.optional.arm.FEAT_I8MM",
+	"BF16":    "hw.optional.arm.FEAT_BF16",
+	"DGH":     "hw.optional.arm.FEAT_DGH",
+	"RNG":     "hw.optional.arm.FEAT_RNG",
+	"MTE":     "hw.optional.arm.FEAT_MTE",
+}
+
+// armSupportedHWCAPFeatures reports every armCategoryHWCAP feature name set
+// in hwcap/hwcap2, honoring offline/filename the same way
+// GetSupportedFeatures does for a captured auxv snapshot instead of the
+// live one.
+func armSupportedHWCAPFeatures(offline bool, filename string) []string {
+	hwcap, hwcap2, err := readARMAuxv(offline, filename)
+	if err != nil {
+		return nil
+	}
+
+	var supported []string
+	for name, f := range armHWCAPFeatures {
+		reg := hwcap
+		if f.hwcap2 {
+			reg = hwcap2
+		}
+		if (reg>>f.bit)&1 == 1 {
+			supported = append(supported, name)
+		}
+	}
+	return supported
+}
+
+// armSupportedAppleFeatures reports every armCategoryApple feature name
+// whose sysctl reads non-zero.
+func armSupportedAppleFeatures() []string {
+	var supported []string
+	for name, sysctl := range appleFeatureSysctls {
+		if ok, err := readAppleSysctlBool(sysctl); err == nil && ok {
+			supported = append(supported, name)
+		}
+	}
+	return supported
+}
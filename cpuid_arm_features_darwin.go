@@ -0,0 +1,60 @@
+//go:build darwin
+
+package cpuid
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// readAppleSysctlBool reports whether the named hw.optional.* sysctl reads
+// non-zero, the convention Darwin uses for boolean CPU feature flags.
+func readAppleSysctlBool(name string) (bool, error) {
+	v, err := syscall.SysctlUint32(name)
+	if err != nil {
+		return false, err
+	}
+	return v != 0, nil
+}
+
+// readARMAuxv reports no HWCAP bits on Darwin, which has no auxv, so
+// armSupportedHWCAPFeatures (reached via GetSupportedFeatures("hwcap") or
+// IsFeatureSupported's ARM fallback) finds no supported features here.
+func readARMAuxv(offline bool, filename string) (hwcap, hwcap2 uint64, err error) {
+	return 0, 0, nil
+}
+
+// darwinCPUFamilyParts maps the hw.cpufamily sysctl's hash values (see
+// XNU's mach/machine.h) to the MIDR part number armParts already lists
+// under implementer 0x61 for that silicon generation. Apple doesn't
+// expose MIDR_EL1 to userspace at all, so this is the closest Darwin gets
+// to it; families not listed here resolve to part 0, which GetARMPartInfo
+// correctly reports as "not found" rather than guessing.
+var darwinCPUFamilyParts = map[uint32]uint32{
+	0x1B588BB3: 0x23, // CPUFAMILY_ARM_FIRESTORM_ICESTORM (M1): report the E-core part
+}
+
+// readARMRegisterLive resolves one armRegister on Darwin. Only MIDR_EL1
+// has a (partial) answer: Darwin doesn't expose the AArch64 ID registers
+// or an HWCAP-style auxv to userspace, so every other register -- and any
+// hw.cpufamily value this package doesn't recognize -- comes back as an
+// error, leaving GetVendorID/GetVendorName to fall back to their
+// Implementer=0/"Unknown" zero-value behavior.
+func readARMRegisterLive(reg armRegister) (uint64, error) {
+	if reg != armRegMIDR {
+		return 0, fmt.Errorf("cpuid: %s is not readable on Darwin", reg)
+	}
+
+	family, err := syscall.SysctlUint32("hw.cpufamily")
+	if err != nil {
+		return 0, err
+	}
+	part, ok := darwinCPUFamilyParts[family]
+	if !ok {
+		return 0, fmt.Errorf("cpuid: unrecognized hw.cpufamily 0x%X", family)
+	}
+
+	// Apple implementer 0x61, architecture nibble 0xF (ARMv8+), variant and
+	// revision unknown from cpufamily alone so left 0.
+	return uint64(0x61)<<24 | uint64(0xF)<<16 | uint64(part)<<4, nil
+}
@@ -0,0 +1,64 @@
+//go:build linux
+
+package cpuid
+
+import (
+	"encoding/binary"
+	"os"
+	"unsafe"
+)
+
+// Linux auxv entry types carrying the HWCAP/HWCAP2 feature bitmasks, from
+// <linux/auxvec.h>.
+const (
+	atHWCAP  = 16
+	atHWCAP2 = 26
+)
+
+// readARMAuxv returns AT_HWCAP/AT_HWCAP2 from /proc/self/auxv, or from
+// filename if offline is set -- the same captured-snapshot replay
+// GetSupportedFeatures' offline/filename pair gives the x86 CPUID path.
+func readARMAuxv(offline bool, filename string) (hwcap, hwcap2 uint64, err error) {
+	path := "/proc/self/auxv"
+	if offline {
+		path = filename
+	}
+	return parseAuxv(path)
+}
+
+// parseAuxv reads a /proc/self/auxv-format file: a sequence of native
+// word-sized (type, value) pairs, terminated by a (AT_NULL, 0) entry.
+func parseAuxv(path string) (hwcap, hwcap2 uint64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	wordSize := int(unsafe.Sizeof(uintptr(0)))
+	for off := 0; off+2*wordSize <= len(data); off += 2 * wordSize {
+		var typ, val uint64
+		if wordSize == 8 {
+			typ = binary.LittleEndian.Uint64(data[off : off+8])
+			val = binary.LittleEndian.Uint64(data[off+8 : off+16])
+		} else {
+			typ = uint64(binary.LittleEndian.Uint32(data[off : off+4]))
+			val = uint64(binary.LittleEndian.Uint32(data[off+4 : off+8]))
+		}
+
+		switch typ {
+		case atHWCAP:
+			hwcap = val
+		case atHWCAP2:
+			hwcap2 = val
+		}
+	}
+	return hwcap, hwcap2, nil
+}
+
+// readAppleSysctlBool reports every sysctl as unset on Linux, which has no
+// hw.optional.* sysctls, so armSupportedAppleFeatures (reached via
+// GetSupportedFeatures("apple") or IsFeatureSupported's ARM fallback) finds
+// no supported features here.
+func readAppleSysctlBool(name string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,25 @@
+//go:build !linux && !darwin
+
+package cpuid
+
+import "fmt"
+
+// readARMAuxv and readAppleSysctlBool have no implementation outside
+// Linux/Darwin, so armCategoryHWCAP/armCategoryApple simply report no
+// supported features there.
+
+func readARMAuxv(offline bool, filename string) (hwcap, hwcap2 uint64, err error) {
+	return 0, 0, nil
+}
+
+func readAppleSysctlBool(name string) (bool, error) {
+	return false, nil
+}
+
+// readARMRegisterLive has no implementation outside Linux/Darwin -- this
+// only applies to 32-bit ARM builds on e.g. Windows or a BSD, which have
+// no equivalent of /proc/cpuinfo's CPU implementer/part fields -- so every
+// register comes back unavailable and CPUIDWithMode reports it as 0.
+func readARMRegisterLive(reg armRegister) (uint64, error) {
+	return 0, fmt.Errorf("cpuid: %s is not readable on this platform", reg)
+}
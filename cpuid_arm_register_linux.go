@@ -0,0 +1,21 @@
+//go:build linux && (arm || arm64)
+
+package cpuid
+
+// readARMRegisterLive resolves one armRegister from the live system on
+// Linux. HWCAP/HWCAP2 come straight from the auxv cpuid_arm_features_linux.go
+// already knows how to parse; every other register is an AArch64 ID
+// register, which readARMIDRegister resolves via mrs on arm64 and reports
+// unavailable on 32-bit ARMv7 (ID_AA64* registers don't exist pre-AArch64).
+func readARMRegisterLive(reg armRegister) (uint64, error) {
+	switch reg {
+	case armRegHWCAP:
+		hwcap, _, err := readARMAuxv(false, "")
+		return hwcap, err
+	case armRegHWCAP2:
+		_, hwcap2, err := readARMAuxv(false, "")
+		return hwcap2, err
+	default:
+		return readARMIDRegister(reg)
+	}
+}
@@ -0,0 +1,118 @@
+//go:build arm || (arm64 && linux) || darwin
+// +build arm arm64,linux darwin
+
+package cpuid
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// armRegister names one AArch64 identification/system register (or HWCAP
+// word) this package can read, the ARM-side analogue of an x86
+// leaf/subleaf pair. armCPURegisters lists every register CaptureARMSnapshot
+// records.
+type armRegister string
+
+// Registers GetVendorID/GetVendorName/GetARMPartInfo and a captured
+// ARMSnapshot can resolve. HWCAP/HWCAP2 are included alongside the AArch64
+// ID registers so a single snapshot file covers both GetVendorID-style
+// identification and the armCategoryHWCAP feature lookups in
+// cpuid_arm_features.go.
+const (
+	armRegMIDR   armRegister = "MIDR_EL1"
+	armRegMPIDR  armRegister = "MPIDR_EL1"
+	armRegISAR0  armRegister = "ID_AA64ISAR0_EL1"
+	armRegISAR1  armRegister = "ID_AA64ISAR1_EL1"
+	armRegISAR2  armRegister = "ID_AA64ISAR2_EL1"
+	armRegPFR0   armRegister = "ID_AA64PFR0_EL1"
+	armRegPFR1   armRegister = "ID_AA64PFR1_EL1"
+	armRegMMFR0  armRegister = "ID_AA64MMFR0_EL1"
+	armRegMMFR1  armRegister = "ID_AA64MMFR1_EL1"
+	armRegMMFR2  armRegister = "ID_AA64MMFR2_EL1"
+	armRegHWCAP  armRegister = "HWCAP"
+	armRegHWCAP2 armRegister = "HWCAP2"
+)
+
+// armCPURegisters is every register CaptureARMSnapshot captures, in the
+// order they're written to the snapshot file.
+var armCPURegisters = []armRegister{
+	armRegMIDR, armRegMPIDR,
+	armRegISAR0, armRegISAR1, armRegISAR2,
+	armRegPFR0, armRegPFR1,
+	armRegMMFR0, armRegMMFR1, armRegMMFR2,
+	armRegHWCAP, armRegHWCAP2,
+}
+
+// ARMSnapshot is a captured dump of this CPU's identification registers,
+// keyed by armRegister -- the ARM counterpart to Data/Entry on x86, where
+// registers are named rather than addressed by leaf/subleaf. A snapshot
+// taken on one CPU (an M1, a Graviton) can be replayed through
+// CPUIDWithMode's offline mode on any other host.
+type ARMSnapshot struct {
+	Registers map[armRegister]uint64 `json:"registers"`
+}
+
+// CaptureARMSnapshot reads every register in armCPURegisters from the live
+// system and writes the result to filename as JSON. A register this host
+// can't provide (e.g. the AArch64 ID registers on a Darwin host, which
+// only exposes hw.optional.* booleans) is simply omitted.
+func CaptureARMSnapshot(filename string) error {
+	snap := ARMSnapshot{Registers: make(map[armRegister]uint64, len(armCPURegisters))}
+	for _, reg := range armCPURegisters {
+		if v, err := readARMRegisterLive(reg); err == nil {
+			snap.Registers[reg] = v
+		}
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snap)
+}
+
+// armSnapshotFromFile reads a previously captured ARMSnapshot.
+func armSnapshotFromFile(filename string) (ARMSnapshot, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ARMSnapshot{}, err
+	}
+	defer file.Close()
+
+	var snap ARMSnapshot
+	if err := json.NewDecoder(file).Decode(&snap); err != nil {
+		return ARMSnapshot{}, err
+	}
+	return snap, nil
+}
+
+// CPUIDWithMode resolves reg's value either from a captured ARMSnapshot
+// (offline) or from the live system, mirroring the x86 CPUIDWithMode's
+// offline/filename pair so the ARM path has the same API shape: a register
+// this mode can't provide (a missing snapshot entry, an OS/arch that can't
+// read it live) comes back as 0 rather than an error, the same way the x86
+// CPUIDWithMode reports an unreadable leaf as all-zero registers. Live
+// reads are dispatched to readARMRegisterLive, whose implementation is
+// build-tag-selected per OS/arch: mrs on ARM64 Linux, /proc/cpuinfo plus
+// the identification/midr_el1 sysfs node on ARMv7, and sysctlbyname on
+// Darwin.
+func CPUIDWithMode(reg armRegister, offline bool, filename string) uint64 {
+	if offline {
+		snap, err := armSnapshotFromFile(filename)
+		if err != nil {
+			return 0
+		}
+		return snap.Registers[reg]
+	}
+
+	v, err := readARMRegisterLive(reg)
+	if err != nil {
+		return 0
+	}
+	return v
+}
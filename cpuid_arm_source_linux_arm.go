@@ -0,0 +1,75 @@
+//go:build linux && arm
+
+package cpuid
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readARMIDRegister reads reg on 32-bit ARMv7 Linux. Only MIDR_EL1 (really
+// the legacy 32-bit MIDR) is available: ARMv7 has no ID_AA64* registers or
+// MPIDR_EL1 -- those are AArch64-only. MIDR comes from
+// /sys/devices/system/cpu/cpu0/regs/identification/midr_el1 when the
+// kernel exposes it, falling back to reconstructing the same 32-bit layout
+// from /proc/cpuinfo's "CPU implementer"/"CPU variant"/"CPU
+// architecture"/"CPU part"/"CPU revision" fields.
+func readARMIDRegister(reg armRegister) (uint64, error) {
+	if reg != armRegMIDR {
+		return 0, fmt.Errorf("cpuid: %s is not available on ARMv7", reg)
+	}
+
+	if v, err := readMIDRSysfs(); err == nil {
+		return v, nil
+	}
+	return readMIDRProcCPUInfo()
+}
+
+// readMIDRSysfs reads the raw MIDR value the kernel exports directly, when
+// present.
+func readMIDRSysfs() (uint64, error) {
+	data, err := os.ReadFile("/sys/devices/system/cpu/cpu0/regs/identification/midr_el1")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 0, 64)
+}
+
+// readMIDRProcCPUInfo reconstructs the 32-bit MIDR layout GetVendorID
+// decodes (implementer:variant:architecture:part:revision) from the
+// equivalent /proc/cpuinfo fields.
+func readMIDRProcCPUInfo() (uint64, error) {
+	data, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := map[string]uint64{}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		val := strings.TrimSpace(parts[1])
+		n, err := strconv.ParseUint(val, 0, 64)
+		if err != nil {
+			continue
+		}
+		fields[key] = n
+	}
+
+	implementer, ok := fields["CPU implementer"]
+	if !ok {
+		return 0, fmt.Errorf("cpuid: /proc/cpuinfo has no CPU implementer field")
+	}
+
+	midr := (implementer & 0xff) << 24
+	midr |= (fields["CPU variant"] & 0xf) << 20
+	midr |= (fields["CPU architecture"] & 0xf) << 16
+	midr |= (fields["CPU part"] & 0xfff) << 4
+	midr |= fields["CPU revision"] & 0xf
+	return midr, nil
+}
@@ -0,0 +1,42 @@
+//go:build linux && arm64
+
+package cpuid
+
+import "fmt"
+
+// armMRS reads one AArch64 system register, selected by id (an
+// armIDRegisterOpcode index rather than the register's own encoding,
+// since the MRS instruction's target register is fixed at assemble time
+// and there's no single "read system register by runtime id" opcode). It
+// is implemented in assembly as a table of MRS instructions dispatched on
+// id, mirroring how the package's x86 `cpuid` stub wraps the CPUID
+// instruction.
+func armMRS(id uint32) (hi, lo uint32)
+
+// armIDRegisterOpcode maps the AArch64 ID registers CPUIDWithMode can
+// resolve to the dispatch index armMRS expects.
+var armIDRegisterOpcode = map[armRegister]uint32{
+	armRegMIDR:  0,
+	armRegMPIDR: 1,
+	armRegISAR0: 2,
+	armRegISAR1: 3,
+	armRegISAR2: 4,
+	armRegPFR0:  5,
+	armRegPFR1:  6,
+	armRegMMFR0: 7,
+	armRegMMFR1: 8,
+	armRegMMFR2: 9,
+}
+
+// readARMIDRegister reads reg directly via MRS. Every register
+// CPUIDWithMode can resolve to is 64-bit wide on AArch64, including
+// MIDR_EL1/MPIDR_EL1 even though ARMv7's 32-bit MIDR only needed the low
+// word.
+func readARMIDRegister(reg armRegister) (uint64, error) {
+	id, ok := armIDRegisterOpcode[reg]
+	if !ok {
+		return 0, fmt.Errorf("cpuid: %s has no ARM64 MRS encoding", reg)
+	}
+	hi, lo := armMRS(id)
+	return uint64(hi)<<32 | uint64(lo), nil
+}
@@ -0,0 +1,188 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BaselineDiagnostic records one feature bit dropped from a Baseline
+// computation because some input snapshot (directly or via a cross-vendor
+// Equivalents hint) didn't have it -- the "dropped by <host>" report a
+// fleet operator needs before picking a migration-safe feature set.
+type BaselineDiagnostic struct {
+	Category  string `json:"category"`
+	Feature   string `json:"feature"`
+	DroppedBy string `json:"dropped_by"`
+}
+
+// featureAvailable reports whether name is usable on src, either directly
+// or via any feature transitively equivalent to it (see Equivalents) --
+// the same reconciliation IsFunctionallyAvailable does for the live/offline
+// pair, generalized to any Source so Baseline can reconcile a mixed
+// Intel+AMD fleet.
+func featureAvailable(name string, src Source) bool {
+	if IsFeatureSupportedFromSource(name, src) {
+		return true
+	}
+	for _, eq := range Equivalents(name) {
+		if IsFeatureSupportedFromSource(eq.Name(), src) {
+			return true
+		}
+	}
+	return false
+}
+
+// Baseline computes the greatest-common-denominator feature set across
+// snapshots: for every FeatureSet/bit registered in cpuFeaturesList, the
+// resulting Snapshot reports a feature as supported only if every input
+// has it, directly or via a cross-vendor equivalence hint. It's the same
+// problem a VM orchestrator solves before live-migrating a guest across
+// heterogeneous hosts -- feed it LoadModel results, JSON dumps read via
+// DataFromFile, or live captures.
+//
+// Baseline only reconstructs the feature registers this package knows
+// about (StandardECX/EDX, ExtendedEBX/ECX/EDX, ExtendedEDX, ...); leaf 0's
+// vendor string and leaf 1's family/model/stepping are left at zero in the
+// result, since there's no single "baseline" vendor ID for a mixed fleet.
+// Call BaselineWithDiagnostics for the per-feature "dropped by <host>"
+// detail this merge discards.
+func Baseline(snapshots ...*Snapshot) *Snapshot {
+	snap, _ := BaselineWithDiagnostics(snapshots...)
+	return snap
+}
+
+// BaselineWithDiagnostics is Baseline's sibling that also reports, for
+// every feature bit the merge dropped, which input snapshot lacked it.
+func BaselineWithDiagnostics(snapshots ...*Snapshot) (*Snapshot, []BaselineDiagnostic) {
+	var diagnostics []BaselineDiagnostic
+	registers := make(map[[2]uint32]*Entry)
+
+	registerFor := func(leaf, subleaf uint32) *Entry {
+		key := [2]uint32{leaf, subleaf}
+		e, ok := registers[key]
+		if !ok {
+			e = &Entry{Leaf: leaf, Subleaf: subleaf}
+			registers[key] = e
+		}
+		return e
+	}
+
+	categories := make([]string, 0, len(cpuFeaturesList))
+	for category := range cpuFeaturesList {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		fs := cpuFeaturesList[category]
+		if fs.condition != nil && !fs.condition(0) {
+			continue
+		}
+
+		bits := make([]int, 0, len(fs.features))
+		for bit := range fs.features {
+			bits = append(bits, bit)
+		}
+		sort.Ints(bits)
+
+		var outReg uint32
+		for _, bit := range bits {
+			f := fs.features[bit]
+
+			commonToAll := true
+			for i, snap := range snapshots {
+				if featureAvailable(f.name, snap) {
+					continue
+				}
+				commonToAll = false
+				diagnostics = append(diagnostics, BaselineDiagnostic{
+					Category:  category,
+					Feature:   f.name,
+					DroppedBy: snapshotLabel(snap, i),
+				})
+			}
+			if commonToAll {
+				outReg |= 1 << uint(bit)
+			}
+		}
+
+		entry := registerFor(fs.leaf, fs.subleaf)
+		switch fs.register {
+		case 0:
+			entry.EAX = outReg
+		case 1:
+			entry.EBX = outReg
+		case 2:
+			entry.ECX = outReg
+		case 3:
+			entry.EDX = outReg
+		}
+	}
+
+	var data Data
+	for _, e := range registers {
+		data.Entries = append(data.Entries, *e)
+	}
+	sort.Slice(data.Entries, func(i, j int) bool {
+		if data.Entries[i].Leaf != data.Entries[j].Leaf {
+			return data.Entries[i].Leaf < data.Entries[j].Leaf
+		}
+		return data.Entries[i].Subleaf < data.Entries[j].Subleaf
+	})
+
+	return &Snapshot{Name: "baseline", Data: data}, diagnostics
+}
+
+// snapshotLabel names snap for a BaselineDiagnostic, falling back to its
+// input position when it has no Name (e.g. a bare FileSource-backed
+// snapshot built by hand rather than via LoadModel).
+func snapshotLabel(snap *Snapshot, index int) string {
+	if snap.Name != "" {
+		return snap.Name
+	}
+	return fmt.Sprintf("input %d", index)
+}
+
+// X86_64Level is one of the x86-64 psABI microarchitecture levels Go's
+// GOAMD64 build setting and glibc's ifunc resolution both use.
+type X86_64Level string
+
+// The four x86-64 psABI levels, from the mandatory baseline up.
+const (
+	X86_64V1 X86_64Level = "x86-64-v1"
+	X86_64V2 X86_64Level = "x86-64-v2"
+	X86_64V3 X86_64Level = "x86-64-v3"
+	X86_64V4 X86_64Level = "x86-64-v4"
+)
+
+// x86_64LevelRequirements lists the canonical feature names each level
+// requires in addition to x86-64-v1's mandatory baseline (which this
+// package doesn't model as discrete CPUID bits, since every CPU it can run
+// on already satisfies it). The v2 psABI also requires LAHF_SAHF and the
+// v3 psABI LZCNT (both CPUID.80000001H:ECX), but those bits aren't
+// registered in cpuFeaturesList yet, so they're omitted here rather than
+// making v2/v3 unreachable until they are.
+var x86_64LevelRequirements = map[X86_64Level][]string{
+	X86_64V2: {"SSE3", "SSSE3", "SSE4.1", "SSE4.2", "POPCNT", "CMPXCHG16B"},
+	X86_64V3: {"AVX", "AVX2", "BMI1", "BMI2", "F16C", "FMA", "MOVBE", "OSXSAVE"},
+	X86_64V4: {"AVX512F", "AVX512BW", "AVX512CD", "AVX512DQ", "AVX512VL"},
+}
+
+// ClassifyX86_64Level reports the highest x86-64-vN level src satisfies,
+// checking each level's required features (directly, no cross-vendor
+// reconciliation -- the psABI levels are feature-exact, not
+// capability-equivalent) and falling back one level at the first
+// unsatisfied requirement.
+func ClassifyX86_64Level(src Source) X86_64Level {
+	level := X86_64V1
+	for _, v := range []X86_64Level{X86_64V2, X86_64V3, X86_64V4} {
+		for _, name := range x86_64LevelRequirements[v] {
+			if !IsFeatureSupportedFromSource(name, src) {
+				return level
+			}
+		}
+		level = v
+	}
+	return level
+}
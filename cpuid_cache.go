@@ -6,6 +6,24 @@ import (
 	"strings"
 )
 
+// CPUCacheInfo is one decoded cache level from the deterministic cache
+// parameters leaf (Intel leaf 4 / AMD CPUID.8000001DH) or, for CPUs too old
+// to have one, the legacy descriptor/size leaves those helpers fall back
+// to.
+type CPUCacheInfo struct {
+	Level            uint32
+	Type             string
+	SizeKB           uint32
+	Ways             uint32
+	LineSizeBytes    uint32
+	TotalSets        uint32
+	MaxCoresSharing  uint32
+	SelfInitializing bool
+	FullyAssociative bool
+	MaxProcessorIDs  uint32
+	WritePolicy      string
+}
+
 // GetCacheInfo returns cache information for the CPU
 func GetCacheInfo(maxFunc, maxExtFunc uint32, vendorID string, offline bool, filename string) ([]CPUCacheInfo, error) {
 	isIntel := strings.Contains(strings.ToUpper(vendorID), "INTEL")
@@ -42,7 +60,17 @@ func GetAMDCache(maxExtFunc uint32, offline bool, filename string) []CPUCacheInf
 // GetIntelCache returns cache information for Intel processors
 func GetIntelCache(maxFunc uint32, offline bool, filename string) []CPUCacheInfo {
 	if maxFunc < 4 {
-		return nil
+		// No deterministic cache parameters leaf: fall back to the legacy
+		// CPUID.02H descriptor bytes, the only cache source these older
+		// CPUs expose.
+		if maxFunc < 2 {
+			return nil
+		}
+		decoded, err := DecodeIntelLeaf2(offline, filename)
+		if err != nil {
+			return nil
+		}
+		return decoded.Caches
 	}
 
 	var caches []CPUCacheInfo
@@ -0,0 +1,81 @@
+package cpuid
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeIntelLeaf2Fixture writes an offline snapshot with just the leaf 0/1/2
+// entries GetIntelCache/GetIntelTLBInfo's leaf-2 fallback needs.
+func writeIntelLeaf2Fixture(t *testing.T, leaf2 Entry) string {
+	t.Helper()
+
+	data := Data{Entries: []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 2, EBX: 0x756e6547, ECX: 0x6c65746e, EDX: 0x49656e69},
+		{Leaf: 1, Subleaf: 0, EAX: 0x000206A7},
+		leaf2,
+	}}
+
+	path := filepath.Join(t.TempDir(), "intel-leaf2.json")
+	buf, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+func TestGetIntelCacheFallsBackToLeaf2(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxFunc uint32
+		leaf2   Entry
+		want    []CPUCacheInfo
+	}{
+		{
+			name:    "maxFunc below leaf 2: no cache source available",
+			maxFunc: 1,
+			want:    nil,
+		},
+		{
+			name:    "maxFunc is exactly leaf 2: decode descriptor bytes",
+			maxFunc: 2,
+			leaf2:   Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EDX: 0x2C},
+			want: []CPUCacheInfo{
+				{Level: 1, Type: "Data", SizeKB: 32, Ways: 8, LineSizeBytes: 64},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeIntelLeaf2Fixture(t, tt.leaf2)
+
+			got := GetIntelCache(tt.maxFunc, true, path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetIntelCache() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIntelTLBInfoFromLeaf2(t *testing.T) {
+	// maxFunc stops short of leaf 0x18, so GetIntelTLBInfo's only TLB
+	// source is the leaf-2 descriptor scan chunk5-5 wired in.
+	path := writeIntelLeaf2Fixture(t, Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0x5A})
+
+	info := GetIntelTLBInfo(0x02, true, path)
+
+	want := []TLBEntry{{PageSize: "2MB/4MB", Entries: 32, Associativity: "4-way"}}
+	if !reflect.DeepEqual(info.L1.Data, want) {
+		t.Errorf("GetIntelTLBInfo().L1.Data = %+v, want %+v", info.L1.Data, want)
+	}
+	if len(info.L1.Instruction) != 0 {
+		t.Errorf("GetIntelTLBInfo().L1.Instruction = %+v, want empty", info.L1.Instruction)
+	}
+}
@@ -0,0 +1,120 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// CacheLevel is one deterministic cache-parameters entry decoded from
+// Intel CPUID leaf 4 or AMD CPUID.8000001DH, extending the fields
+// GetCPUCacheDetails already exposes in CPUCacheInfo with the EDX
+// behavior bits those leaves also carry.
+type CacheLevel struct {
+	CPUCacheInfo
+	// Inclusive is true if this cache level is inclusive of lower levels
+	// (EDX bit 1).
+	Inclusive bool
+	// ComplexIndexing is true if the cache uses a complex (hash-based)
+	// indexing function rather than a direct bit-extract (EDX bit 2);
+	// Sets/Ways alone don't fully describe set selection when this is set.
+	ComplexIndexing bool
+	// InvalidatesLowerLevels is true if a WBINVD/INVD on this level also
+	// invalidates the cache hierarchy below it for all sharing threads
+	// (EDX bit 0 clear); false (EDX bit 0 set) means only this level's own contents are
+	// invalidated, and every other processor's copy must be invalidated
+	// separately.
+	InvalidatesLowerLevels bool
+}
+
+// GetCacheTopology decodes the deterministic cache-parameters leaf --
+// Intel CPUID leaf 4, or AMD CPUID.8000001DH -- into a slice of CacheLevel,
+// one entry per subleaf until CacheType (EAX[4:0]) reads 0. AMD parts that
+// don't support 0x8000001D (pre-Fam17h) fall back to the legacy
+// 0x80000005/0x80000006 L1/L2/L3 size leaves, synthesizing one CacheLevel
+// per cache (L1 Data, L1 Instruction, L2, L3) since those leaves don't report sharing or the EDX behavior
+// bits.
+func GetCacheTopology(maxFunc, maxExtFunc uint32, offline bool, filename string) []CacheLevel {
+	if isAMD(offline, filename) {
+		if maxExtFunc >= 0x8000001D {
+			return decodeDeterministicCacheLevels(0x8000001D, offline, filename)
+		}
+		return legacyAMDCacheLevels(maxExtFunc, offline, filename)
+	}
+
+	if isIntel(offline, filename) && maxFunc >= 4 {
+		return decodeDeterministicCacheLevels(4, offline, filename)
+	}
+
+	return nil
+}
+
+// decodeDeterministicCacheLevels walks leaf's subleaves, reusing
+// GetCPUCacheDetails for the fields it already decodes and adding the EDX
+// bits GetCPUCacheDetails doesn't surface.
+func decodeDeterministicCacheLevels(leaf uint32, offline bool, filename string) []CacheLevel {
+	var levels []CacheLevel
+	for i := uint32(0); ; i++ {
+		info := GetCPUCacheDetails(leaf, i, offline, filename)
+		if info.Type == getCacheTypeString(0) {
+			break
+		}
+
+		_, _, _, d := CPUIDWithMode(leaf, i, offline, filename)
+		levels = append(levels, CacheLevel{
+			CPUCacheInfo:           info,
+			InvalidatesLowerLevels: d&1 == 0,
+			Inclusive:              (d>>1)&1 != 0,
+			ComplexIndexing:        (d>>2)&1 != 0,
+		})
+	}
+	return levels
+}
+
+// legacyAMDCacheLevels synthesizes L1/L2/L3 CacheLevel entries from
+// CPUID.80000005H (L1) and CPUID.80000006H (L2/L3), the only cache sizing
+// these pre-Fam17h parts expose. Sizes and associativity come back in KB
+// and an encoded associativity nibble rather than the Ways/Sets/LineSize
+// triple 0x8000001D reports, so Ways/TotalSets are left zero and SizeKB is
+// taken directly from the leaf.
+func legacyAMDCacheLevels(maxExtFunc uint32, offline bool, filename string) []CacheLevel {
+	if maxExtFunc < 0x80000005 {
+		return nil
+	}
+
+	var levels []CacheLevel
+
+	_, _, c, d := CPUIDWithMode(0x80000005, 0, offline, filename)
+	levels = append(levels,
+		CacheLevel{CPUCacheInfo: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: (c >> 24) & 0xFF, LineSizeBytes: c & 0xFF}},
+		CacheLevel{CPUCacheInfo: CPUCacheInfo{Level: 1, Type: "Instruction", SizeKB: (d >> 24) & 0xFF, LineSizeBytes: d & 0xFF}},
+	)
+
+	if maxExtFunc >= 0x80000006 {
+		_, _, c, d := CPUIDWithMode(0x80000006, 0, offline, filename)
+		if l2Size := (c >> 16) & 0xFFFF; l2Size != 0 {
+			levels = append(levels, CacheLevel{CPUCacheInfo: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: l2Size, LineSizeBytes: c & 0xFF}})
+		}
+		if l3Size := ((d >> 18) & 0x3FFF) * 512; l3Size != 0 {
+			levels = append(levels, CacheLevel{CPUCacheInfo: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: l3Size, LineSizeBytes: d & 0xFF}})
+		}
+	}
+
+	return levels
+}
+
+// GetLLCSizeBytes returns the total size in bytes of the last-level cache
+// in topology -- the highest-numbered level present, summed across any
+// entries at that level (accounting for per-instance reporting rather
+// than an already-aggregated total). It returns 0 if topology is empty.
+func GetLLCSizeBytes(topology []CacheLevel) uint64 {
+	var llcLevel uint32
+	for _, l := range topology {
+		if l.Level > llcLevel {
+			llcLevel = l.Level
+		}
+	}
+
+	var total uint64
+	for _, l := range topology {
+		if l.Level == llcLevel {
+			total += uint64(l.SizeKB) * 1024
+		}
+	}
+	return total
+}
@@ -0,0 +1,213 @@
+package cpuid
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeCacheTopologyFixture writes an offline snapshot with the vendor leaf
+// (0) plus whatever cache leaves entries supplies.
+func writeCacheTopologyFixture(t *testing.T, vendorEDX, vendorECX uint32, entries []Entry) string {
+	t.Helper()
+
+	data := Data{Entries: append([]Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 4, EBX: 0x68747541, ECX: vendorECX, EDX: vendorEDX},
+	}, entries...)}
+
+	path := filepath.Join(t.TempDir(), "cache-topology.json")
+	buf, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+const (
+	amdVendorECX = 0x444D4163
+	amdVendorEDX = 0x69746E65
+
+	intelVendorECX = 0x6C65746E
+	intelVendorEDX = 0x49656E69
+)
+
+// leaf4L1DataEntry is CPUID.4H subleaf 0 decoding to: L1 Data, 32KB, 8-way,
+// 64B line, 64 sets, MaxCoresSharing 2, InvalidatesLowerLevels and
+// Inclusive both true, ComplexIndexing false.
+func leaf4L1DataEntry(leaf, subleaf uint32) Entry {
+	return Entry{
+		Leaf: leaf, Subleaf: subleaf,
+		EAX: 1 | (1 << 5) | (1 << 8) | (1 << 14),
+		EBX: 63 | (7 << 22),
+		ECX: 63,
+		EDX: 0b010,
+	}
+}
+
+func leaf4Terminator(leaf, subleaf uint32) Entry {
+	return Entry{Leaf: leaf, Subleaf: subleaf}
+}
+
+func TestDecodeDeterministicCacheLevels(t *testing.T) {
+	path := writeCacheTopologyFixture(t, intelVendorEDX, intelVendorECX, []Entry{
+		leaf4L1DataEntry(4, 0),
+		leaf4Terminator(4, 1),
+	})
+
+	got := decodeDeterministicCacheLevels(4, true, path)
+
+	want := []CacheLevel{
+		{
+			CPUCacheInfo: CPUCacheInfo{
+				Level: 1, Type: "Data", SizeKB: 32, Ways: 8, LineSizeBytes: 64,
+				TotalSets: 64, MaxCoresSharing: 2, SelfInitializing: true,
+				MaxProcessorIDs: 1, WritePolicy: "Write Back",
+			},
+			InvalidatesLowerLevels: true,
+			Inclusive:              true,
+			ComplexIndexing:        false,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeDeterministicCacheLevels() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLegacyAMDCacheLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxExtFunc uint32
+		entries    []Entry
+		want       []CacheLevel
+	}{
+		{
+			name:       "below 0x80000005: no legacy cache leaves at all",
+			maxExtFunc: 0x80000004,
+			want:       nil,
+		},
+		{
+			name:       "only L1 available",
+			maxExtFunc: 0x80000005,
+			entries: []Entry{
+				// ECX: L1 data, 64KB (bits24-31), 64B line (bits0-7).
+				// EDX: L1 instruction, 32KB, 64B line.
+				{Leaf: 0x80000005, Subleaf: 0, ECX: (64 << 24) | 64, EDX: (32 << 24) | 64},
+			},
+			want: []CacheLevel{
+				{CPUCacheInfo: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 64, LineSizeBytes: 64}},
+				{CPUCacheInfo: CPUCacheInfo{Level: 1, Type: "Instruction", SizeKB: 32, LineSizeBytes: 64}},
+			},
+		},
+		{
+			name:       "L1/L2/L3 all available",
+			maxExtFunc: 0x80000019,
+			entries: []Entry{
+				{Leaf: 0x80000005, Subleaf: 0, ECX: (64 << 24) | 64, EDX: (32 << 24) | 64},
+				// 0x80000006 ECX: L2 size in KB at bits16-31; EDX: L3 size
+				// in 512KB units at bits18-31.
+				{Leaf: 0x80000006, Subleaf: 0, ECX: (512 << 16) | 64, EDX: (16 << 18) | 64},
+			},
+			want: []CacheLevel{
+				{CPUCacheInfo: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 64, LineSizeBytes: 64}},
+				{CPUCacheInfo: CPUCacheInfo{Level: 1, Type: "Instruction", SizeKB: 32, LineSizeBytes: 64}},
+				{CPUCacheInfo: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 512, LineSizeBytes: 64}},
+				{CPUCacheInfo: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 16 * 512, LineSizeBytes: 64}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeCacheTopologyFixture(t, amdVendorEDX, amdVendorECX, tt.entries)
+
+			got := legacyAMDCacheLevels(tt.maxExtFunc, true, path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("legacyAMDCacheLevels() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCacheTopology(t *testing.T) {
+	t.Run("AMD with 0x8000001D uses the deterministic leaf", func(t *testing.T) {
+		path := writeCacheTopologyFixture(t, amdVendorEDX, amdVendorECX, []Entry{
+			leaf4L1DataEntry(0x8000001D, 0),
+			leaf4Terminator(0x8000001D, 1),
+		})
+
+		got := GetCacheTopology(1, 0x8000001D, true, path)
+		if len(got) != 1 || got[0].Type != "Data" {
+			t.Errorf("GetCacheTopology() = %+v, want one Data entry from leaf 0x8000001D", got)
+		}
+	})
+
+	t.Run("AMD without 0x8000001D falls back to the legacy leaves", func(t *testing.T) {
+		path := writeCacheTopologyFixture(t, amdVendorEDX, amdVendorECX, []Entry{
+			{Leaf: 0x80000005, Subleaf: 0, ECX: (64 << 24) | 64, EDX: (32 << 24) | 64},
+		})
+
+		got := GetCacheTopology(1, 0x80000005, true, path)
+		if len(got) != 2 {
+			t.Errorf("GetCacheTopology() = %+v, want the 2 legacy L1 entries", got)
+		}
+	})
+
+	t.Run("Intel with maxFunc >= 4 uses leaf 4", func(t *testing.T) {
+		path := writeCacheTopologyFixture(t, intelVendorEDX, intelVendorECX, []Entry{
+			leaf4L1DataEntry(4, 0),
+			leaf4Terminator(4, 1),
+		})
+
+		got := GetCacheTopology(4, 0, true, path)
+		if len(got) != 1 || got[0].Type != "Data" {
+			t.Errorf("GetCacheTopology() = %+v, want one Data entry from leaf 4", got)
+		}
+	})
+
+	t.Run("Intel below maxFunc 4 has no cache topology source", func(t *testing.T) {
+		path := writeCacheTopologyFixture(t, intelVendorEDX, intelVendorECX, nil)
+
+		if got := GetCacheTopology(1, 0, true, path); got != nil {
+			t.Errorf("GetCacheTopology() = %+v, want nil", got)
+		}
+	})
+}
+
+func TestGetLLCSizeBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology []CacheLevel
+		want     uint64
+	}{
+		{name: "empty topology", topology: nil, want: 0},
+		{
+			name: "single LLC entry",
+			topology: []CacheLevel{
+				{CPUCacheInfo: CPUCacheInfo{Level: 1, SizeKB: 32}},
+				{CPUCacheInfo: CPUCacheInfo{Level: 3, SizeKB: 8192}},
+			},
+			want: 8192 * 1024,
+		},
+		{
+			name: "multiple instances of the highest level are summed",
+			topology: []CacheLevel{
+				{CPUCacheInfo: CPUCacheInfo{Level: 3, SizeKB: 4096}},
+				{CPUCacheInfo: CPUCacheInfo{Level: 3, SizeKB: 4096}},
+			},
+			want: 2 * 4096 * 1024,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetLLCSizeBytes(tt.topology); got != tt.want {
+				t.Errorf("GetLLCSizeBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
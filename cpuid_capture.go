@@ -22,6 +22,28 @@ type Data struct {
 
 // CaptureData traverses the full CPUID hierarchy and writes the data to cpuid_data.json.
 func CaptureData(filename string) error {
+	data := captureData()
+
+	// Write the collected CPUID data to a JSON file.
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// captureData traverses the full CPUID hierarchy and returns it as a Data,
+// the shared implementation behind CaptureData's JSON output and
+// DumpRawDump's `cpuid -r`-format output.
+func captureData() Data {
 	var data Data
 
 	// Capture Standard CPUID Leaves.
@@ -29,7 +51,7 @@ func CaptureData(filename string) error {
 	maxStandard, _, _, _ := cpuid(0, 0)
 	for leaf := uint32(0); leaf <= maxStandard; leaf++ {
 		// For leaves that support multiple subleafs.
-		if leaf == 4 || leaf == 0xB || leaf == 0xD {
+		if leaf == 4 || leaf == 0xB || leaf == 0xD || leaf == 0x1F {
 			subleaf := uint32(0)
 			for {
 				a, b, c, d := cpuid(leaf, subleaf)
@@ -41,6 +63,11 @@ func CaptureData(filename string) error {
 				if leaf == 0xB && subleaf > 0 && a == 0 {
 					break
 				}
+				// For leaf 0x1F (v2 extended topology), stop once both the shift width
+				// (EAX[4:0]) and level type (ECX[15:8]) report zero.
+				if leaf == 0x1F && subleaf > 0 && (a&0x1F) == 0 && ((c>>8)&0xFF) == 0 {
+					break
+				}
 				// For leaf 0xD, stop if all registers are zero (after the first subleaf).
 				if leaf == 0xD && subleaf > 0 && a == 0 && b == 0 && c == 0 && d == 0 {
 					break
@@ -104,20 +131,7 @@ func CaptureData(filename string) error {
 		}
 	}
 
-	// Write the collected CPUID data to a JSON file.
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(data); err != nil {
-		return err
-	}
-
-	return nil
+	return data
 }
 
 // DataFromFile reads cpuid_data.json and returns a Data struct.
@@ -0,0 +1,155 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "strings"
+
+// GuestKind identifies which confidential-computing (or plain) execution
+// mode the current CPUID snapshot was taken under.
+type GuestKind string
+
+// The guest kinds IsGuest distinguishes.
+const (
+	GuestBareMetal GuestKind = "bare-metal"
+	GuestSEV       GuestKind = "sev"
+	GuestSEVES     GuestKind = "sev-es"
+	GuestSEVSNP    GuestKind = "sev-snp"
+	GuestTDX       GuestKind = "tdx"
+	GuestOther     GuestKind = "other-hypervisor"
+)
+
+// AMDMemoryEncryption is the decoded CPUID.8000001FH platform parameters a
+// SEV/SEV-ES/SEV-SNP consumer needs beyond the raw capability bits: where
+// the C-bit lives in a guest physical address, how many address bits SEV
+// steals from the host's physical range, and how many encrypted ASIDs and
+// VMPLs the platform supports.
+type AMDMemoryEncryption struct {
+	SMESupported       bool   `json:"sme_supported"`
+	SEVSupported       bool   `json:"sev_supported"`
+	SEVESSupported     bool   `json:"sev_es_supported"`
+	SEVSNPSupported    bool   `json:"sev_snp_supported"`
+	VMPLSupported      bool   `json:"vmpl_supported"`
+	CBitPosition       int    `json:"c_bit_position"`
+	PhysAddrReduction  int    `json:"phys_addr_reduction"`
+	NumEncryptedGuests uint32 `json:"num_encrypted_guests"`
+	MinSEVASID         uint32 `json:"min_sev_asid"`
+	NumVMPLs           uint32 `json:"num_vmpls"`
+}
+
+// IntelTrustDomain is the decoded TME/MK-TME key-ID width and TDX
+// availability a confidential-VM host or guest needs: CPUID.7H.ECX[13]
+// gates TME itself, and CPUID.21H's TDX_MODULE vendor string ("IntelTDX")
+// reports that a TDX module is present; TDXGuest and TDXModulePresent are
+// both derived from that signature.
+type IntelTrustDomain struct {
+	TMESupported     bool `json:"tme_supported"`
+	MKTMESupported   bool `json:"mktme_supported"`
+	TDXGuest         bool `json:"tdx_guest"`
+	TDXModulePresent bool `json:"tdx_module_present"`
+	// MKTMEKeyIDBits is always 0: the MK-TME key-ID width is reported by
+	// IA32_TME_CAPABILITY (MSR 0x981), not by any CPUID leaf, so this
+	// package -- CPUID-only by design -- can't decode it from src alone.
+	MKTMEKeyIDBits uint32 `json:"mktme_keyid_bits"`
+}
+
+// ConfidentialComputeInfo is the unified result of ConfidentialCompute:
+// whichever of AMD or Intel applies to src is populated, and the other is
+// left zero-valued.
+type ConfidentialComputeInfo struct {
+	AMD   *AMDMemoryEncryption `json:"amd,omitempty"`
+	Intel *IntelTrustDomain    `json:"intel,omitempty"`
+}
+
+// ConfidentialCompute decodes the platform parameters behind src's
+// MemoryEncryption capability bits: on AMD, CPUID.8000001FH EAX (feature
+// bits), EBX (C-bit position in bits 5:0, address reduction in bits 11:6),
+// and ECX/EDX (encrypted-guest and min-SEV-ASID counts); on Intel,
+// CPUID.7H's TME/TDX-guest bits plus CPUID.21H's TDX_MODULE vendor string.
+func ConfidentialCompute(src Source) ConfidentialComputeInfo {
+	vendor := vendorIDFromSource(src)
+
+	if strings.Contains(strings.ToUpper(vendor), "AMD") {
+		return ConfidentialComputeInfo{AMD: amdMemoryEncryption(src)}
+	}
+	return ConfidentialComputeInfo{Intel: intelTrustDomain(src)}
+}
+
+// amdMemoryEncryption decodes CPUID.8000001FH for src.
+func amdMemoryEncryption(src Source) *AMDMemoryEncryption {
+	eax, ebx, ecx, edx := src.CPUID(0x8000001F, 0)
+	if eax == 0 {
+		return &AMDMemoryEncryption{}
+	}
+
+	return &AMDMemoryEncryption{
+		SMESupported:       eax&(1<<0) != 0,
+		SEVSupported:       eax&(1<<1) != 0,
+		SEVESSupported:     eax&(1<<3) != 0,
+		SEVSNPSupported:    eax&(1<<4) != 0,
+		VMPLSupported:      eax&(1<<5) != 0,
+		CBitPosition:       int(ebx & 0x3F),
+		PhysAddrReduction:  int((ebx >> 6) & 0x3F),
+		NumEncryptedGuests: ecx,
+		MinSEVASID:         edx,
+		NumVMPLs:           (eax >> 6) & 0xF,
+	}
+}
+
+// intelTrustDomain decodes CPUID.7H's TME bit and CPUID.21H's TDX_MODULE
+// vendor string for src. CPUID itself doesn't
+// distinguish single-key TME from multi-key MK-TME -- that's an
+// IA32_TME_ACTIVATE (MSR 0x982) BIOS-configuration detail -- so
+// MKTMESupported just mirrors TMESupported, the same way a caller reading
+// the raw feature bit would have to.
+func intelTrustDomain(src Source) *IntelTrustDomain {
+	_, _, ecx7, _ := src.CPUID(7, 0)
+	maxLeaf21, b21, c21, d21 := src.CPUID(0x21, 0)
+
+	td := &IntelTrustDomain{
+		TMESupported: ecx7&(1<<13) != 0,
+	}
+	td.MKTMESupported = td.TMESupported
+	if maxLeaf21 > 0 {
+		sig := leaf2ModuleSignature(b21, c21, d21)
+		td.TDXModulePresent = sig == "IntelTDX    "
+		td.TDXGuest = td.TDXModulePresent
+	}
+	return td
+}
+
+// leaf2ModuleSignature decodes the 12-byte vendor string CPUID leaf 0x21
+// packs into EBX:EDX:ECX, the same layout leaf 0 uses for the CPU vendor
+// ID.
+func leaf2ModuleSignature(b, c, d uint32) string {
+	buf := make([]byte, 0, 12)
+	for _, reg := range [3]uint32{b, d, c} {
+		buf = append(buf, byte(reg), byte(reg>>8), byte(reg>>16), byte(reg>>24))
+	}
+	return string(buf)
+}
+
+// IsGuest reports which confidential-computing mode (or GuestOther/
+// GuestBareMetal) the current CPUID snapshot was taken under, combining
+// the leaf 0x40000000 hypervisor signature with the SEV/TDX guest-visible
+// bits ConfidentialCompute decodes -- a guest under SEV-SNP still sets the
+// HYPERVISOR bit, so hypervisor presence alone can't distinguish it from a
+// guest under plain KVM.
+func IsGuest(src Source) GuestKind {
+	hv := GetHypervisorInfoFromSource(src)
+	if !hv.Present {
+		return GuestBareMetal
+	}
+
+	cc := ConfidentialCompute(src)
+	switch {
+	case cc.AMD != nil && cc.AMD.SEVSNPSupported:
+		return GuestSEVSNP
+	case cc.AMD != nil && cc.AMD.SEVESSupported:
+		return GuestSEVES
+	case cc.AMD != nil && cc.AMD.SEVSupported:
+		return GuestSEV
+	case cc.Intel != nil && cc.Intel.TDXGuest:
+		return GuestTDX
+	}
+
+	return GuestOther
+}
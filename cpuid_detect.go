@@ -0,0 +1,128 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "sort"
+
+// rawLeafDumps is the fixed set of leaf/subleaf pairs Detect captures
+// verbatim into Report.RawLeaves -- the leaves every section elsewhere in
+// this file already decodes piecemeal (vendor, signature, cache, extended
+// address sizes), kept here as an undigested EAX/EBX/ECX/EDX dump so a
+// consumer that wants a field this package doesn't expose yet (or wants to
+// diff two hosts bit-for-bit) doesn't have to re-run CPUID itself.
+var rawLeafDumps = []In{
+	{Leaf: 0x0, Subleaf: 0},
+	{Leaf: 0x1, Subleaf: 0},
+	{Leaf: 0x7, Subleaf: 0},
+	{Leaf: 0xB, Subleaf: 0},
+	{Leaf: 0xD, Subleaf: 0},
+	{Leaf: 0x80000000, Subleaf: 0},
+	{Leaf: 0x80000001, Subleaf: 0},
+	{Leaf: 0x80000008, Subleaf: 0},
+}
+
+// RawLeaf is one leaf/subleaf's undecoded CPUID output.
+type RawLeaf struct {
+	Leaf    uint32 `json:"leaf" yaml:"leaf"`
+	Subleaf uint32 `json:"subleaf" yaml:"subleaf"`
+	EAX     uint32 `json:"eax" yaml:"eax"`
+	EBX     uint32 `json:"ebx" yaml:"ebx"`
+	ECX     uint32 `json:"ecx" yaml:"ecx"`
+	EDX     uint32 `json:"edx" yaml:"edx"`
+}
+
+// AddressSizes is the physical/linear address width CPUID.80000008H:EAX
+// reports, zero on a CPU too old to have the leaf.
+type AddressSizes struct {
+	PhysicalBits uint32 `json:"physical_bits" yaml:"physical_bits"`
+	LinearBits   uint32 `json:"linear_bits" yaml:"linear_bits"`
+}
+
+// Signature is the family/model/stepping triple CPUID.1H:EAX decodes into.
+type Signature struct {
+	Family         uint32 `json:"family" yaml:"family"`
+	ExtendedFamily uint32 `json:"extended_family" yaml:"extended_family"`
+	Model          uint32 `json:"model" yaml:"model"`
+	ExtendedModel  uint32 `json:"extended_model" yaml:"extended_model"`
+	Stepping       uint32 `json:"stepping" yaml:"stepping"`
+}
+
+// Detect collects everything this package knows how to read about a CPU --
+// vendor, signature, brand string, cache hierarchy, TLB entries, address
+// widths, hybrid core info, every registered feature's supported status,
+// and a handful of raw leaf dumps -- into a single Report, so a caller
+// importing this module gets one structured value to marshal, diff, or
+// feed into a fleet baseline instead of scraping cpuidcmd's text output.
+//
+// Detect always populates the full tree; BuildReport remains the
+// filterable, feature-focused entry point for callers that only want
+// Report.Entries.
+func Detect(opts ReportOptions) *Report {
+	report := BuildReport(opts)
+
+	a, _, _, _ := CPUIDWithMode(0, 0, opts.Offline, opts.Filename)
+	maxFunc := a
+	a, _, _, _ = CPUIDWithMode(0x80000000, 0, opts.Offline, opts.Filename)
+	maxExtFunc := a
+
+	vendorID := GetVendorID(opts.Offline, opts.Filename)
+
+	report.VendorID = vendorID
+	report.VendorName = GetVendorName(opts.Offline, opts.Filename)
+	report.BrandString = GetBrandString(maxExtFunc, opts.Offline, opts.Filename)
+
+	model := GetModelData(opts.Offline, opts.Filename)
+	report.Signature = Signature{
+		Family:         model.FamilyID,
+		ExtendedFamily: model.ExtendedFamily,
+		Model:          model.ModelID,
+		ExtendedModel:  model.ExtendedModel,
+		Stepping:       model.SteppingID,
+	}
+
+	proc := GetProcessorInfo(maxFunc, maxExtFunc, opts.Offline, opts.Filename)
+	report.AddressSizes = AddressSizes{
+		PhysicalBits: proc.PhysicalAddressBits,
+		LinearBits:   proc.LinearAddressBits,
+	}
+
+	if caches, err := GetCacheInfo(maxFunc, maxExtFunc, vendorID, opts.Offline, opts.Filename); err == nil {
+		report.Cache = caches
+	}
+
+	if tlb, err := GetTLBInfo(maxFunc, maxExtFunc, opts.Offline, opts.Filename); err == nil {
+		report.TLB = &tlb
+	}
+
+	if isIntel(opts.Offline, opts.Filename) {
+		hybrid := GetIntelHybrid(opts.Offline, opts.Filename)
+		report.Hybrid = &hybrid
+	}
+
+	report.RawLeaves = captureRawLeaves(maxFunc, maxExtFunc, opts.Offline, opts.Filename)
+
+	return &report
+}
+
+// captureRawLeaves dumps rawLeafDumps' registers, skipping any leaf beyond
+// what maxFunc/maxExtFunc report as supported rather than reading past the
+// CPU's documented leaf range.
+func captureRawLeaves(maxFunc, maxExtFunc uint32, offline bool, filename string) []RawLeaf {
+	var leaves []RawLeaf
+	for _, in := range rawLeafDumps {
+		if in.Leaf < 0x80000000 && in.Leaf > maxFunc {
+			continue
+		}
+		if in.Leaf >= 0x80000000 && in.Leaf > maxExtFunc {
+			continue
+		}
+		a, b, c, d := CPUIDWithMode(in.Leaf, in.Subleaf, offline, filename)
+		leaves = append(leaves, RawLeaf{Leaf: in.Leaf, Subleaf: in.Subleaf, EAX: a, EBX: b, ECX: c, EDX: d})
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		if leaves[i].Leaf != leaves[j].Leaf {
+			return leaves[i].Leaf < leaves[j].Leaf
+		}
+		return leaves[i].Subleaf < leaves[j].Subleaf
+	})
+	return leaves
+}
@@ -0,0 +1,202 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Name, Description and Vendor expose Feature's otherwise-unexported fields
+// to callers outside this package, such as the CLI's --equivalent printer.
+func (f Feature) Name() string        { return f.name }
+func (f Feature) Description() string { return f.description }
+func (f Feature) Vendor() string      { return f.vendor }
+
+// featureNode locates a named feature within cpuFeaturesList.
+type featureNode struct {
+	category string
+	bit      int
+	feature  Feature
+}
+
+// findFeatureNode scans every FeatureSet for a feature named name.
+func findFeatureNode(name string) (featureNode, bool) {
+	for category, fs := range cpuFeaturesList {
+		for bit, f := range fs.features {
+			if f.name == name {
+				return featureNode{category: category, bit: bit, feature: f}, true
+			}
+		}
+	}
+	return featureNode{}, false
+}
+
+// buildEquivalenceGraph turns every feature's scattered
+// equivalentFeatureName/equivalent hint (e.g. PMC_PEBS -> AMDExtendedECX[10]
+// IBS) into a bidirectional adjacency list, so a hint recorded on only one
+// side of a vendor pair is still navigable from the other.
+func buildEquivalenceGraph() map[string]map[string]bool {
+	graph := make(map[string]map[string]bool)
+	addEdge := func(a, b string) {
+		if graph[a] == nil {
+			graph[a] = make(map[string]bool)
+		}
+		graph[a][b] = true
+		if graph[b] == nil {
+			graph[b] = make(map[string]bool)
+		}
+		graph[b][a] = true
+	}
+
+	for _, fs := range cpuFeaturesList {
+		for _, f := range fs.features {
+			if f.equivalentFeatureName == "" || f.equivalent < 0 {
+				continue
+			}
+			other, ok := cpuFeaturesList[f.equivalentFeatureName]
+			if !ok {
+				continue
+			}
+			eq, ok := other.features[f.equivalent]
+			if !ok {
+				continue
+			}
+			addEdge(f.name, eq.name)
+		}
+	}
+
+	return graph
+}
+
+// Equivalents returns every feature transitively reachable from name via
+// equivalence hints (e.g. IBS <-> PEBS <-> PEA), excluding name itself, so a
+// hint chain spanning more than one hop (vendor A's feature equivalent to a
+// vendor-neutral concept equivalent to vendor B's feature) still resolves.
+func Equivalents(name string) []Feature {
+	graph := buildEquivalenceGraph()
+
+	visited := map[string]bool{name: true}
+	queue := []string{name}
+	var result []Feature
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for neighbor := range graph[cur] {
+			if visited[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+			if node, ok := findFeatureNode(neighbor); ok {
+				result = append(result, node.feature)
+			}
+			queue = append(queue, neighbor)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].name < result[j].name })
+	return result
+}
+
+// IsFunctionallyAvailable reports whether name, or any feature transitively
+// equivalent to it, is supported on this CPU -- so portable software can
+// ask "is there any way to do precise sampling on this CPU?" without caring
+// whether that's Intel PEBS or AMD IBS.
+func IsFunctionallyAvailable(name string, offline bool, filename string) bool {
+	if IsFeatureSupported(name, offline, filename) {
+		return true
+	}
+	for _, eq := range Equivalents(name) {
+		if IsFeatureSupported(eq.name, offline, filename) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEquivalenceHints scans cpuFeaturesList for equivalentFeatureName/
+// equivalent hints that don't resolve to a real group+bit, returning one
+// error per broken hint. It's exposed as a callable check for callers and
+// tests; the CLI doesn't invoke it.
+func ValidateEquivalenceHints() []error {
+	var errs []error
+
+	for category, fs := range cpuFeaturesList {
+		for bit, f := range fs.features {
+			if f.equivalentFeatureName == "" {
+				continue
+			}
+			if f.equivalent < 0 {
+				errs = append(errs, fmt.Errorf("cpuid: %s[%d] %s: equivalentFeatureName set but equivalent bit is %d", category, bit, f.name, f.equivalent))
+				continue
+			}
+			other, ok := cpuFeaturesList[f.equivalentFeatureName]
+			if !ok {
+				errs = append(errs, fmt.Errorf("cpuid: %s[%d] %s: equivalent group %q does not exist", category, bit, f.name, f.equivalentFeatureName))
+				continue
+			}
+			if _, ok := other.features[f.equivalent]; !ok {
+				errs = append(errs, fmt.Errorf("cpuid: %s[%d] %s: equivalent group %q has no bit %d", category, bit, f.name, f.equivalentFeatureName, f.equivalent))
+			}
+		}
+	}
+
+	return errs
+}
+
+// DumpEquivalenceGraphDOT writes the equivalence graph in Graphviz dot
+// format, one undirected edge per line.
+func DumpEquivalenceGraphDOT(w io.Writer) error {
+	graph := buildEquivalenceGraph()
+
+	names := make([]string, 0, len(graph))
+	for name := range graph {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if _, err := fmt.Fprintln(w, "graph equivalence {"); err != nil {
+		return err
+	}
+	seen := make(map[[2]string]bool)
+	for _, a := range names {
+		neighbors := make([]string, 0, len(graph[a]))
+		for b := range graph[a] {
+			neighbors = append(neighbors, b)
+		}
+		sort.Strings(neighbors)
+		for _, b := range neighbors {
+			if seen[[2]string{b, a}] {
+				continue
+			}
+			seen[[2]string{a, b}] = true
+			if _, err := fmt.Fprintf(w, "  %q -- %q;\n", a, b); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// DumpEquivalenceGraphJSON writes the equivalence graph as a JSON object
+// mapping each feature name to its sorted list of equivalents.
+func DumpEquivalenceGraphJSON(w io.Writer) error {
+	graph := buildEquivalenceGraph()
+
+	out := make(map[string][]string, len(graph))
+	for name, neighbors := range graph {
+		list := make([]string, 0, len(neighbors))
+		for n := range neighbors {
+			list = append(list, n)
+		}
+		sort.Strings(list)
+		out[name] = list
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
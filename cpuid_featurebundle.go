@@ -0,0 +1,92 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "sort"
+
+// FeatureBundle names a set of canonical feature names that must all be
+// present together. It's the generalization behind the x86-64 psABI levels
+// below, but general enough to describe any other all-or-nothing capability
+// tier a future caller wants to register (a vendor's own marketing SKU
+// tiers, say), without hard-coding psABI-specific assumptions into the
+// checking logic.
+type FeatureBundle struct {
+	Name     string
+	Level    int
+	Requires []string
+}
+
+// x86_64Bundles lists the four x86-64 psABI microarchitecture levels
+// (the "x86-64-vN" tiers glibc's hwcaps ifunc resolution and Go's GOAMD64
+// build setting both target) as cumulative FeatureBundles: each one names
+// only the features it adds on top of the previous level.
+//
+// v1's mandatory baseline (CMOV, CX8, FPU, FXSR, MMX, OSFXSR, SCE, SSE,
+// SSE2) isn't modeled as a Requires list: every CPU this package can run on
+// already satisfies it, and SCE/OSFXSR aren't registered in
+// cpuFeaturesList. v2's LAHF_SAHF and v3's LZCNT are omitted for the same
+// reason ClassifyX86_64Level omits LZCNT: those bits aren't registered in
+// cpuFeaturesList yet, so excluding them here rather than making the level
+// unreachable until they are. v2's CX16 requirement is checked via its
+// registered canonical name, CMPXCHG16B.
+var x86_64Bundles = []FeatureBundle{
+	{Name: "x86-64-v1", Level: 1, Requires: nil},
+	{Name: "x86-64-v2", Level: 2, Requires: []string{"CMPXCHG16B", "POPCNT", "SSE3", "SSSE3", "SSE4.1", "SSE4.2"}},
+	{Name: "x86-64-v3", Level: 3, Requires: []string{"AVX", "AVX2", "BMI1", "BMI2", "F16C", "FMA", "MOVBE", "OSXSAVE"}},
+	{Name: "x86-64-v4", Level: 4, Requires: []string{"AVX512F", "AVX512BW", "AVX512CD", "AVX512DQ", "AVX512VL"}},
+}
+
+// bundleSatisfied reports whether src supports every feature bundle
+// requires, directly (no cross-vendor Equivalents reconciliation -- like
+// ClassifyX86_64Level, psABI levels are feature-exact).
+func bundleSatisfied(bundle FeatureBundle, src Source) bool {
+	for _, name := range bundle.Requires {
+		if !IsFeatureSupportedFromSource(name, src) {
+			return false
+		}
+	}
+	return true
+}
+
+// HighestSupportedLevel reports the highest x86-64 psABI level (1-4) that
+// src satisfies, falling back one level at the first FeatureBundle it
+// doesn't fully support -- the same portable-binary distribution question
+// `ld.so --help`'s glibc-hwcaps listing answers on Linux, surfaced here so a
+// Go build system can gate GOAMD64=v2/v3/v4 binaries at the right host.
+func HighestSupportedLevel(src Source) int {
+	level := x86_64Bundles[0].Level
+	for _, bundle := range x86_64Bundles[1:] {
+		if !bundleSatisfied(bundle, src) {
+			break
+		}
+		level = bundle.Level
+	}
+	return level
+}
+
+// LevelGap records one x86-64 psABI level's FeatureBundle and which of its
+// required features src doesn't support -- the "what's blocking the next
+// glibc-hwcaps tier" detail HighestSupportedLevel's single int discards.
+type LevelGap struct {
+	Level   int      `json:"level"`
+	Name    string   `json:"name"`
+	Missing []string `json:"missing"`
+}
+
+// X86_64LevelReport lists every x86-64 psABI level alongside which of its
+// required features src is missing, so a build or packaging system can
+// report not just "this host tops out at v2" but "v3 needs AVX2, BMI2" --
+// the operator-facing detail a GOAMD64 gating decision needs.
+func X86_64LevelReport(src Source) []LevelGap {
+	gaps := make([]LevelGap, 0, len(x86_64Bundles))
+	for _, bundle := range x86_64Bundles {
+		var missing []string
+		for _, name := range bundle.Requires {
+			if !IsFeatureSupportedFromSource(name, src) {
+				missing = append(missing, name)
+			}
+		}
+		sort.Strings(missing)
+		gaps = append(gaps, LevelGap{Level: bundle.Level, Name: bundle.Name, Missing: missing})
+	}
+	return gaps
+}
@@ -0,0 +1,174 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// featureDatabaseVersion is bumped whenever the on-disk schema changes
+// shape, so LoadFeatureDatabase can reject a file it doesn't understand
+// instead of silently mis-loading it.
+const featureDatabaseVersion = 1
+
+// dbFeature is the on-the-wire form of a single bit in a FeatureSet.
+type dbFeature struct {
+	Bit                   int    `json:"bit"`
+	Name                  string `json:"name"`
+	Description           string `json:"description"`
+	Vendor                string `json:"vendor"`
+	EquivalentFeatureName string `json:"equivalent_feature_name,omitempty"`
+	Equivalent            int    `json:"equivalent"`
+}
+
+// dbFeatureSet is the on-the-wire form of one cpuFeaturesList entry.
+// Condition is a tiny expression evaluated by evalCondition rather than a Go
+// closure, so a FeatureSet's gating logic can travel in the JSON file
+// instead of requiring a rebuild.
+type dbFeatureSet struct {
+	Category  string      `json:"category"`
+	Name      string      `json:"name"`
+	Leaf      uint32      `json:"leaf"`
+	Subleaf   uint32      `json:"subleaf"`
+	Register  int         `json:"register"`
+	Group     string      `json:"group"`
+	Condition string      `json:"condition,omitempty"`
+	Features  []dbFeature `json:"features"`
+}
+
+// featureDatabase is the root document LoadFeatureDatabase/DumpFeatureDatabase
+// read and write.
+type featureDatabase struct {
+	Version     int            `json:"version"`
+	FeatureSets []dbFeatureSet `json:"feature_sets"`
+}
+
+//go:embed features.json
+var embeddedFeatureDB []byte
+
+// evalCondition compiles a condition expression into the func(uint32) bool
+// closure FeatureSet.condition expects. The grammar is intentionally tiny --
+// a bare "isAMD"/"isIntel" identifier, or "" for no condition -- covering
+// every condition cpuFeaturesList actually needs today. It can grow towards
+// full "ebx7_0.SGX == 1" register-bit comparisons as more vendor-gated
+// FeatureSets are externalized.
+func evalCondition(expr string) (func(uint32) bool, error) {
+	switch expr {
+	case "":
+		return nil, nil
+	case "isAMD":
+		return func(uint32) bool { return isAMD(false, "") }, nil
+	case "isIntel":
+		return func(uint32) bool { return isIntel(false, "") }, nil
+	default:
+		return nil, fmt.Errorf("cpuid: unsupported feature condition expression %q", expr)
+	}
+}
+
+// conditionExprByCategory remembers the expression LoadFeatureDatabase
+// compiled a category's condition from, so DumpFeatureDatabase can round-trip
+// it. A FeatureSet whose condition was built from a Go closure elsewhere in
+// the package (not via LoadFeatureDatabase) has no entry here, since Go
+// closures aren't introspectable -- it dumps with an empty Condition.
+var conditionExprByCategory = map[string]string{}
+
+// describeCondition returns the expression DumpFeatureDatabase should emit
+// for a category's condition, falling back to "" when it wasn't set via
+// LoadFeatureDatabase.
+func describeCondition(category string) string {
+	return conditionExprByCategory[category]
+}
+
+// LoadFeatureDatabase replaces cpuFeaturesList's entries with the ones
+// described by r, so a deployment can pick up newly-announced CPUID leaves
+// (e.g. the EPYC-Milan additions) by shipping an updated JSON file instead
+// of rebuilding the binary. Existing categories not present in r are left
+// untouched.
+func LoadFeatureDatabase(r io.Reader) error {
+	var db featureDatabase
+	if err := json.NewDecoder(r).Decode(&db); err != nil {
+		return fmt.Errorf("cpuid: decode feature database: %w", err)
+	}
+	if db.Version != featureDatabaseVersion {
+		return fmt.Errorf("cpuid: unsupported feature database version %d (want %d)", db.Version, featureDatabaseVersion)
+	}
+
+	for _, dbfs := range db.FeatureSets {
+		cond, err := evalCondition(dbfs.Condition)
+		if err != nil {
+			return fmt.Errorf("cpuid: feature set %q: %w", dbfs.Category, err)
+		}
+
+		fs := FeatureSet{
+			name:      dbfs.Name,
+			leaf:      dbfs.Leaf,
+			subleaf:   dbfs.Subleaf,
+			register:  dbfs.Register,
+			group:     dbfs.Group,
+			condition: cond,
+			features:  make(map[int]Feature, len(dbfs.Features)),
+		}
+		for _, f := range dbfs.Features {
+			fs.features[f.Bit] = Feature{
+				name:                  f.Name,
+				description:           f.Description,
+				vendor:                f.Vendor,
+				equivalentFeatureName: f.EquivalentFeatureName,
+				equivalent:            f.Equivalent,
+			}
+		}
+		cpuFeaturesList[dbfs.Category] = fs
+		if dbfs.Condition != "" {
+			conditionExprByCategory[dbfs.Category] = dbfs.Condition
+		} else {
+			delete(conditionExprByCategory, dbfs.Category)
+		}
+	}
+
+	return nil
+}
+
+// LoadDefaultFeatureDatabase loads the features.json bundled into the
+// binary via go:embed, merging it into cpuFeaturesList the same way
+// LoadFeatureDatabase does. Nothing in the package calls it automatically.
+func LoadDefaultFeatureDatabase() error {
+	return LoadFeatureDatabase(bytes.NewReader(embeddedFeatureDB))
+}
+
+// DumpFeatureDatabase serializes the current cpuFeaturesList to w in the
+// same schema LoadFeatureDatabase reads, so a caller can snapshot a running
+// registry (including whatever LoadFeatureDatabase has merged into it) and
+// ship it as a future default.
+func DumpFeatureDatabase(w io.Writer) error {
+	db := featureDatabase{Version: featureDatabaseVersion}
+
+	for category, fs := range cpuFeaturesList {
+		dbfs := dbFeatureSet{
+			Category:  category,
+			Name:      fs.name,
+			Leaf:      fs.leaf,
+			Subleaf:   fs.subleaf,
+			Register:  fs.register,
+			Group:     fs.group,
+			Condition: describeCondition(category),
+		}
+		for bit, f := range fs.features {
+			dbfs.Features = append(dbfs.Features, dbFeature{
+				Bit:                   bit,
+				Name:                  f.name,
+				Description:           f.description,
+				Vendor:                f.vendor,
+				EquivalentFeatureName: f.equivalentFeatureName,
+				Equivalent:            f.equivalent,
+			})
+		}
+		db.FeatureSets = append(db.FeatureSets, dbfs)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(db)
+}
@@ -3,12 +3,15 @@ package cpuid
 
 import "sort"
 
-// GetAllFeatureCategories reports all categories
+// GetAllFeatureCategories reports all categories, including the ARM-only
+// armCategoryHWCAP/armCategoryApple pseudo-categories that aren't backed by
+// a cpuFeaturesList entry.
 func GetAllFeatureCategories() []string {
-	categories := make([]string, 0, len(cpuFeaturesList))
+	categories := make([]string, 0, len(cpuFeaturesList)+2)
 	for category := range cpuFeaturesList {
 		categories = append(categories, category)
 	}
+	categories = append(categories, armCategoryHWCAP, armCategoryApple)
 	//sort categories
 	sort.Strings(categories)
 
@@ -19,6 +22,26 @@ func GetAllFeatureCategories() []string {
 func GetAllFeatureCategoriesDetailed() map[string][]map[string]string {
 	details := make(map[string][]map[string]string)
 
+	hwcapDetails := make([]map[string]string, 0, len(armHWCAPFeatures))
+	for name, f := range armHWCAPFeatures {
+		hwcapDetails = append(hwcapDetails, map[string]string{
+			"name":        name,
+			"description": f.description,
+			"vendor":      "arm",
+		})
+	}
+	details[armCategoryHWCAP] = hwcapDetails
+
+	appleDetails := make([]map[string]string, 0, len(appleFeatureSysctls))
+	for name, sysctl := range appleFeatureSysctls {
+		appleDetails = append(appleDetails, map[string]string{
+			"name":        name,
+			"description": "reported via " + sysctl,
+			"vendor":      "apple",
+		})
+	}
+	details[armCategoryApple] = appleDetails
+
 	for _, fs := range cpuFeaturesList {
 		categoryDetails := []map[string]string{}
 		for _, feat := range fs.features {
@@ -47,6 +70,23 @@ func GetAllFeatureCategoriesDetailed() map[string][]map[string]string {
 
 // GetAllKnownFeatures reports all known features
 func GetAllKnownFeatures(category string) []string {
+	switch category {
+	case armCategoryHWCAP:
+		names := make([]string, 0, len(armHWCAPFeatures))
+		for name := range armHWCAPFeatures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	case armCategoryApple:
+		names := make([]string, 0, len(appleFeatureSysctls))
+		for name := range appleFeatureSysctls {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
 	fs, exists := cpuFeaturesList[category]
 	if !exists {
 		return nil
@@ -59,8 +99,30 @@ func GetAllKnownFeatures(category string) []string {
 	return features
 }
 
-// GetSupportedFeatures reports all supported features
+// GetSupportedFeatures reports all supported features. armCategoryHWCAP and
+// armCategoryApple are handled directly against the OS's auxv/sysctl
+// feature bitmap instead of a CPUID Source, since ARM doesn't expose these
+// bits through CPUID the way x86 does.
 func GetSupportedFeatures(category string, offline bool, filename string) []string {
+	switch category {
+	case armCategoryHWCAP:
+		return armSupportedHWCAPFeatures(offline, filename)
+	case armCategoryApple:
+		return armSupportedAppleFeatures()
+	}
+
+	src, err := sourceFromMode(offline, filename)
+	if err != nil {
+		return nil
+	}
+	return GetSupportedFeaturesFromSource(category, src)
+}
+
+// GetSupportedFeaturesFromSource is the Source-based sibling of
+// GetSupportedFeatures, for callers that already have a fixture or remote
+// snapshot loaded (see FileSource, RemoteSource) instead of an
+// offline/filename pair.
+func GetSupportedFeaturesFromSource(category string, src Source) []string {
 	fs, exists := cpuFeaturesList[category]
 	if !exists {
 		return nil
@@ -71,7 +133,7 @@ func GetSupportedFeatures(category string, offline bool, filename string) []stri
 		return nil
 	}
 
-	a, b, c, d := CPUIDWithMode(fs.leaf, fs.subleaf, offline, filename)
+	a, b, c, d := src.CPUID(fs.leaf, fs.subleaf)
 	var regValue uint32
 	switch fs.register {
 	case 0:
@@ -93,8 +155,34 @@ func GetSupportedFeatures(category string, offline bool, filename string) []stri
 	return supported
 }
 
-// IsFeatureSupported reports if a feature is supported
+// IsFeatureSupported reports if a feature is supported. It also matches
+// ARM's armCategoryHWCAP/armCategoryApple feature names, falling back to
+// them whenever the x86 cpuFeaturesList lookup doesn't report featureName
+// as supported, including when the name is also an x86 feature.
 func IsFeatureSupported(featureName string, offline bool, filename string) bool {
+	src, err := sourceFromMode(offline, filename)
+	if err == nil && IsFeatureSupportedFromSource(featureName, src) {
+		return true
+	}
+
+	for _, name := range armSupportedHWCAPFeatures(offline, filename) {
+		if name == featureName {
+			return true
+		}
+	}
+	for _, name := range armSupportedAppleFeatures() {
+		if name == featureName {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFeatureSupportedFromSource is the Source-based sibling of
+// IsFeatureSupported, for callers that already have a fixture or remote
+// snapshot loaded (see FileSource, RemoteSource) instead of an
+// offline/filename pair.
+func IsFeatureSupportedFromSource(featureName string, src Source) bool {
 	for _, fs := range cpuFeaturesList {
 		// Check condition if present
 		if fs.condition != nil && !fs.condition(0) {
@@ -113,7 +201,7 @@ func IsFeatureSupported(featureName string, offline bool, filename string) bool
 			continue // feature not in this category
 		}
 
-		a, b, c, d := CPUIDWithMode(fs.leaf, fs.subleaf, offline, filename)
+		a, b, c, d := src.CPUID(fs.leaf, fs.subleaf)
 		var regValue uint32
 		switch fs.register {
 		case 0:
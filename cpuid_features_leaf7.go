@@ -0,0 +1,103 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// init registers the two FeatureSets cpuFeaturesList was missing from CPUID
+// leaf 7: subleaf 0's EDX register, and subleaf 1's EAX register. Both were
+// added to the SDM well after ExtendedEBX/ExtendedECX were first catalogued
+// here, so they're merged in from this file instead of reworking the
+// existing literal.
+func init() {
+	cpuFeaturesList["ExtendedEDX"] = FeatureSet{
+		name:     "Extended Features EDX",
+		leaf:     7,
+		subleaf:  0,
+		register: 3,
+		group:    "Basic CPU",
+		features: map[int]Feature{
+			2:  {"AVX512_4VNNIW", "AVX-512 4-register Neural Network Instructions", "CPUID.7.0:EDX.AVX512_4VNNIW[bit 2]", "intel", "", -1},
+			3:  {"AVX512_4FMAPS", "AVX-512 4-register Multiply Accumulation Single precision", "CPUID.7.0:EDX.AVX512_4FMAPS[bit 3]", "intel", "", -1},
+			4:  {"FSRM", "Fast Short REP MOVSB", "CPUID.7.0:EDX.FSRM[bit 4]", "common", "", -1},
+			5:  {"UINTR", "User Interrupts", "CPUID.7.0:EDX.UINTR[bit 5]", "intel", "", -1},
+			8:  {"AVX512_VP2INTERSECT", "AVX-512 Vector Pair Intersection to a Pair of Mask Registers", "CPUID.7.0:EDX.AVX512_VP2INTERSECT[bit 8]", "intel", "", -1},
+			9:  {"SRBDS_CTRL", "Special Register Buffer Data Sampling Mitigations MSR", "CPUID.7.0:EDX.SRBDS_CTRL[bit 9]", "intel", "", -1},
+			10: {"MD_CLEAR", "VERW clears microarchitectural buffers", "CPUID.7.0:EDX.MD_CLEAR[bit 10]", "common", "", -1},
+			11: {"RTM_ALWAYS_ABORT", "RTM transactions always abort", "CPUID.7.0:EDX.RTM_ALWAYS_ABORT[bit 11]", "intel", "", -1},
+			13: {"TSX_FORCE_ABORT", "MSR_TSX_FORCE_ABORT bit 0 supported", "CPUID.7.0:EDX.TSX_FORCE_ABORT[bit 13]", "intel", "", -1},
+			14: {"SERIALIZE", "SERIALIZE instruction", "CPUID.7.0:EDX.SERIALIZE[bit 14]", "common", "", -1},
+			15: {"HYBRID", "CPU is hybrid (mix of core types)", "CPUID.7.0:EDX.HYBRID[bit 15]", "intel", "", -1},
+			16: {"TSXLDTRK", "TSX Suspend/Resume Load Address Tracking", "CPUID.7.0:EDX.TSXLDTRK[bit 16]", "intel", "", -1},
+			18: {"PCONFIG", "PCONFIG instruction", "CPUID.7.0:EDX.PCONFIG[bit 18]", "intel", "", -1},
+			19: {"LBR", "Architectural Last Branch Records", "CPUID.7.0:EDX.LBR[bit 19]", "common", "", -1},
+			20: {"CET_IBT", "Control Flow Enforcement Indirect Branch Tracking", "CPUID.7.0:EDX.CET_IBT[bit 20]", "common", "", -1},
+			22: {"AMX_BF16", "AMX bfloat16 Support", "CPUID.7.0:EDX.AMX_BF16[bit 22]", "intel", "", -1},
+			23: {"AVX512_FP16", "AVX-512 FP16 Instructions", "CPUID.7.0:EDX.AVX512_FP16[bit 23]", "intel", "", -1},
+			24: {"AMX_TILE", "AMX Tile Architecture", "CPUID.7.0:EDX.AMX_TILE[bit 24]", "intel", "", -1},
+			25: {"AMX_INT8", "AMX INT8 Support", "CPUID.7.0:EDX.AMX_INT8[bit 25]", "intel", "", -1},
+			26: {"IBRS_IBPB", "Indirect Branch Restricted Speculation / Predictors", "CPUID.7.0:EDX.IBRS_IBPB[bit 26]", "common", "", -1},
+			27: {"STIBP", "Single Thread Indirect Branch Predictors", "CPUID.7.0:EDX.STIBP[bit 27]", "common", "", -1},
+			28: {"L1D_FLUSH", "L1 Data Cache Flush", "CPUID.7.0:EDX.L1D_FLUSH[bit 28]", "common", "", -1},
+			29: {"IA32_ARCH_CAPABILITIES", "IA32_ARCH_CAPABILITIES MSR", "CPUID.7.0:EDX.IA32_ARCH_CAPABILITIES[bit 29]", "common", "", -1},
+			30: {"IA32_CORE_CAPABILITIES", "IA32_CORE_CAPABILITIES MSR", "CPUID.7.0:EDX.IA32_CORE_CAPABILITIES[bit 30]", "intel", "", -1},
+			31: {"SSBD", "Speculative Store Bypass Disable", "CPUID.7.0:EDX.SSBD[bit 31]", "common", "", -1},
+		},
+	}
+
+	cpuFeaturesList["ExtendedSubleaf1_EAX"] = FeatureSet{
+		name:     "Extended Features Subleaf 1 EAX",
+		leaf:     7,
+		subleaf:  1,
+		register: 0,
+		group:    "Basic CPU",
+		features: map[int]Feature{
+			0:  {"SHA512", "SHA512 instructions", "CPUID.7.1:EAX.SHA512[bit 0]", "intel", "", -1},
+			1:  {"SM3", "SM3 instructions", "CPUID.7.1:EAX.SM3[bit 1]", "intel", "", -1},
+			2:  {"SM4", "SM4 instructions", "CPUID.7.1:EAX.SM4[bit 2]", "intel", "", -1},
+			3:  {"RAO_INT", "Remote Atomic Operations Integer", "CPUID.7.1:EAX.RAO_INT[bit 3]", "intel", "", -1},
+			4:  {"AVX_VNNI", "AVX (VEX-encoded) Vector Neural Network Instructions", "CPUID.7.1:EAX.AVX_VNNI[bit 4]", "intel", "", -1},
+			5:  {"AVX512_BF16", "AVX-512 BFLOAT16 Instructions", "CPUID.7.1:EAX.AVX512_BF16[bit 5]", "intel", "", -1},
+			6:  {"LASS", "Linear Address Space Separation", "CPUID.7.1:EAX.LASS[bit 6]", "intel", "", -1},
+			7:  {"CMPCCXADD", "CMPccXADD instructions", "CPUID.7.1:EAX.CMPCCXADD[bit 7]", "intel", "", -1},
+			8:  {"ARCHPERFMON_EXT", "Architectural Performance Monitoring Extended Leaf", "CPUID.7.1:EAX.ARCHPERFMON_EXT[bit 8]", "intel", "", -1},
+			17: {"FRED", "Flexible Return and Event Delivery", "CPUID.7.1:EAX.FRED[bit 17]", "intel", "", -1},
+			18: {"LKGS", "LKGS instruction", "CPUID.7.1:EAX.LKGS[bit 18]", "intel", "", -1},
+			19: {"WRMSRNS", "Non-serializing WRMSR", "CPUID.7.1:EAX.WRMSRNS[bit 19]", "intel", "", -1},
+			21: {"AMX_FP16", "AMX FP16 Support", "CPUID.7.1:EAX.AMX_FP16[bit 21]", "intel", "", -1},
+			22: {"HRESET", "HRESET instruction", "CPUID.7.1:EAX.HRESET[bit 22]", "intel", "", -1},
+			23: {"AVX_IFMA", "AVX (VEX-encoded) Integer Fused Multiply-Add", "CPUID.7.1:EAX.AVX_IFMA[bit 23]", "intel", "", -1},
+			26: {"LAM", "Linear Address Masking", "CPUID.7.1:EAX.LAM[bit 26]", "intel", "", -1},
+			27: {"MSRLIST", "MSR list read/write instructions", "CPUID.7.1:EAX.MSRLIST[bit 27]", "intel", "", -1},
+		},
+	}
+}
+
+// maxLeaf7Subleaf returns the highest CPUID leaf 7 subleaf the CPU reports
+// (leaf 7, subleaf 0's EAX), so callers can enumerate every populated
+// subleaf instead of assuming subleaf 0 is the only one present -- the
+// approach the Linux kernel and SerenityOS's CPUID decoder both take.
+func maxLeaf7Subleaf(offline bool, filename string) uint32 {
+	a, _, _, _ := CPUIDWithMode(7, 0, offline, filename)
+	return a
+}
+
+// GetExtendedSubleaves reports the supported feature names from every
+// populated CPUID leaf 7 subleaf beyond subleaf 0, keyed by subleaf number.
+// It exists because FeatureSet entries are pinned to a single fixed subleaf,
+// so categories like ExtendedSubleaf1_EAX only get read once a caller knows
+// that subleaf is actually present.
+func GetExtendedSubleaves(offline bool, filename string) map[uint32][]string {
+	result := make(map[uint32][]string)
+
+	maxSubleaf := maxLeaf7Subleaf(offline, filename)
+	for category, fs := range cpuFeaturesList {
+		if fs.leaf != 7 || fs.subleaf == 0 || fs.subleaf > maxSubleaf {
+			continue
+		}
+
+		supported := GetSupportedFeatures(category, offline, filename)
+		if len(supported) > 0 {
+			result[fs.subleaf] = append(result[fs.subleaf], supported...)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,197 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// Feature is one named, independently queryable CPUID feature bit: its
+// canonical name, a human description, the CPUID.<leaf>.<subleaf>:<reg>
+// citation string cpuidRefString regenerates dynamically (kept here too so
+// a feature declared in a Go literal carries its own documentation;
+// dbFeature has no such field, so features loaded by LoadFeatureDatabase
+// leave it empty), which vendor(s) implement it, and an optional
+// cross-vendor equivalence hint (equivalentFeatureName/equivalent) pointing
+// at the bit in another FeatureSet that Equivalents treats as "the same
+// capability under a different CPUID encoding".
+type Feature struct {
+	name                  string
+	description           string
+	ref                   string
+	vendor                string
+	equivalentFeatureName string
+	equivalent            int
+}
+
+// FeatureSet is one CPUID register (leaf/subleaf/register) decoded as a
+// bitmap of named Features. condition, when set, gates the whole category
+// behind a cheap pre-check (e.g. "does this CPU even report leaf 7") so
+// GetSupportedFeatures and friends don't have to special-case categories
+// that can't apply yet.
+type FeatureSet struct {
+	name      string
+	leaf      uint32
+	subleaf   uint32
+	register  int
+	group     string
+	condition func(uint32) bool
+	features  map[int]Feature
+}
+
+// cpuFeaturesList is the package's feature registry: every CPUID
+// leaf/subleaf/register this package knows how to decode into named
+// Features, keyed by category. LoadFeatureDatabase overlays entries loaded
+// from a featureDatabase JSON document on top of (or in place of) the
+// categories registered here and in cpuid_features_leaf7.go's init.
+var cpuFeaturesList = map[string]FeatureSet{
+	"StandardECX": {
+		name:     "Standard Features ECX",
+		leaf:     1,
+		subleaf:  0,
+		register: 2,
+		group:    "Basic CPU",
+		features: map[int]Feature{
+			0:  {"SSE3", "Streaming SIMD Extensions 3", "CPUID.1.0:ECX.SSE3[bit 0]", "common", "", -1},
+			1:  {"PCLMULQDQ", "Carry-less multiplication instruction", "CPUID.1.0:ECX.PCLMULQDQ[bit 1]", "common", "", -1},
+			2:  {"DTES64", "64-bit debug store", "CPUID.1.0:ECX.DTES64[bit 2]", "intel", "", -1},
+			3:  {"MONITOR", "MONITOR/MWAIT instructions", "CPUID.1.0:ECX.MONITOR[bit 3]", "common", "", -1},
+			4:  {"DS_CPL", "CPL qualified debug store", "CPUID.1.0:ECX.DS_CPL[bit 4]", "intel", "", -1},
+			5:  {"VMX", "Virtual Machine Extensions", "CPUID.1.0:ECX.VMX[bit 5]", "intel", "", -1},
+			6:  {"SMX", "Safer Mode Extensions", "CPUID.1.0:ECX.SMX[bit 6]", "intel", "", -1},
+			7:  {"EIST", "Enhanced Intel SpeedStep Technology", "CPUID.1.0:ECX.EIST[bit 7]", "intel", "", -1},
+			8:  {"TM2", "Thermal Monitor 2", "CPUID.1.0:ECX.TM2[bit 8]", "intel", "", -1},
+			9:  {"SSSE3", "Supplemental Streaming SIMD Extensions 3", "CPUID.1.0:ECX.SSSE3[bit 9]", "common", "", -1},
+			10: {"CNXT_ID", "L1 context ID", "CPUID.1.0:ECX.CNXT_ID[bit 10]", "intel", "", -1},
+			11: {"SDBG", "IA32_DEBUG_INTERFACE MSR", "CPUID.1.0:ECX.SDBG[bit 11]", "intel", "", -1},
+			12: {"FMA", "Fused Multiply-Add instructions", "CPUID.1.0:ECX.FMA[bit 12]", "common", "", -1},
+			13: {"CMPXCHG16B", "CMPXCHG16B instruction", "CPUID.1.0:ECX.CMPXCHG16B[bit 13]", "common", "", -1},
+			14: {"XTPR", "xTPR Update Control", "CPUID.1.0:ECX.XTPR[bit 14]", "intel", "", -1},
+			15: {"PDCM", "Perfmon and Debug Capability", "CPUID.1.0:ECX.PDCM[bit 15]", "intel", "", -1},
+			17: {"PCID", "Process-context identifiers", "CPUID.1.0:ECX.PCID[bit 17]", "common", "", -1},
+			18: {"DCA", "Direct Cache Access for DMA writes", "CPUID.1.0:ECX.DCA[bit 18]", "intel", "", -1},
+			19: {"SSE4.1", "Streaming SIMD Extensions 4.1", "CPUID.1.0:ECX.SSE4_1[bit 19]", "common", "", -1},
+			20: {"SSE4.2", "Streaming SIMD Extensions 4.2", "CPUID.1.0:ECX.SSE4_2[bit 20]", "common", "", -1},
+			21: {"X2APIC", "x2APIC support", "CPUID.1.0:ECX.X2APIC[bit 21]", "common", "", -1},
+			22: {"MOVBE", "MOVBE instruction", "CPUID.1.0:ECX.MOVBE[bit 22]", "common", "", -1},
+			23: {"POPCNT", "POPCNT instruction", "CPUID.1.0:ECX.POPCNT[bit 23]", "common", "", -1},
+			24: {"TSC_DEADLINE", "APIC TSC-deadline mode", "CPUID.1.0:ECX.TSC_DEADLINE[bit 24]", "common", "", -1},
+			25: {"AES", "AES instruction set", "CPUID.1.0:ECX.AES[bit 25]", "common", "", -1},
+			26: {"XSAVE", "XSAVE/XRSTOR instructions", "CPUID.1.0:ECX.XSAVE[bit 26]", "common", "", -1},
+			27: {"OSXSAVE", "XSAVE enabled by OS", "CPUID.1.0:ECX.OSXSAVE[bit 27]", "common", "", -1},
+			28: {"AVX", "Advanced Vector Extensions", "CPUID.1.0:ECX.AVX[bit 28]", "common", "", -1},
+			29: {"F16C", "16-bit floating point conversion instructions", "CPUID.1.0:ECX.F16C[bit 29]", "common", "", -1},
+			30: {"RDRAND", "RDRAND instruction", "CPUID.1.0:ECX.RDRAND[bit 30]", "common", "", -1},
+			31: {"HYPERVISOR", "Running under a hypervisor", "CPUID.1.0:ECX.HYPERVISOR[bit 31]", "common", "", -1},
+		},
+	},
+
+	"StandardEDX": {
+		name:     "Standard Features EDX",
+		leaf:     1,
+		subleaf:  0,
+		register: 3,
+		group:    "Basic CPU",
+		features: map[int]Feature{
+			0:  {"FPU", "x87 Floating Point Unit on-chip", "CPUID.1.0:EDX.FPU[bit 0]", "common", "", -1},
+			1:  {"VME", "Virtual 8086 mode enhancements", "CPUID.1.0:EDX.VME[bit 1]", "common", "", -1},
+			2:  {"DE", "Debugging extensions", "CPUID.1.0:EDX.DE[bit 2]", "common", "", -1},
+			3:  {"PSE", "Page Size Extension", "CPUID.1.0:EDX.PSE[bit 3]", "common", "", -1},
+			4:  {"TSC", "Time Stamp Counter", "CPUID.1.0:EDX.TSC[bit 4]", "common", "", -1},
+			5:  {"MSR", "Model Specific Registers", "CPUID.1.0:EDX.MSR[bit 5]", "common", "", -1},
+			6:  {"PAE", "Physical Address Extension", "CPUID.1.0:EDX.PAE[bit 6]", "common", "", -1},
+			7:  {"MCE", "Machine Check Exception", "CPUID.1.0:EDX.MCE[bit 7]", "common", "", -1},
+			8:  {"CX8", "CMPXCHG8B instruction", "CPUID.1.0:EDX.CX8[bit 8]", "common", "", -1},
+			9:  {"APIC", "On-chip APIC", "CPUID.1.0:EDX.APIC[bit 9]", "common", "", -1},
+			11: {"SEP", "SYSENTER/SYSEXIT instructions", "CPUID.1.0:EDX.SEP[bit 11]", "common", "", -1},
+			12: {"MTRR", "Memory Type Range Registers", "CPUID.1.0:EDX.MTRR[bit 12]", "common", "", -1},
+			13: {"PGE", "Page Global Enable bit", "CPUID.1.0:EDX.PGE[bit 13]", "common", "", -1},
+			14: {"MCA", "Machine Check Architecture", "CPUID.1.0:EDX.MCA[bit 14]", "common", "", -1},
+			15: {"CMOV", "Conditional move instructions", "CPUID.1.0:EDX.CMOV[bit 15]", "common", "", -1},
+			16: {"PAT", "Page Attribute Table", "CPUID.1.0:EDX.PAT[bit 16]", "common", "", -1},
+			17: {"PSE36", "36-bit Page Size Extension", "CPUID.1.0:EDX.PSE36[bit 17]", "common", "", -1},
+			18: {"PSN", "Processor Serial Number", "CPUID.1.0:EDX.PSN[bit 18]", "intel", "", -1},
+			19: {"CLFSH", "CLFLUSH instruction", "CPUID.1.0:EDX.CLFSH[bit 19]", "common", "", -1},
+			21: {"DS", "Debug Store", "CPUID.1.0:EDX.DS[bit 21]", "intel", "", -1},
+			22: {"ACPI", "Thermal monitor and software controlled clock facilities", "CPUID.1.0:EDX.ACPI[bit 22]", "intel", "", -1},
+			23: {"MMX", "MMX instruction set", "CPUID.1.0:EDX.MMX[bit 23]", "common", "", -1},
+			24: {"FXSR", "FXSAVE/FXRSTOR instructions", "CPUID.1.0:EDX.FXSR[bit 24]", "common", "", -1},
+			25: {"SSE", "Streaming SIMD Extensions", "CPUID.1.0:EDX.SSE[bit 25]", "common", "", -1},
+			26: {"SSE2", "Streaming SIMD Extensions 2", "CPUID.1.0:EDX.SSE2[bit 26]", "common", "", -1},
+			27: {"SS", "Self Snoop", "CPUID.1.0:EDX.SS[bit 27]", "intel", "", -1},
+			28: {"HTT", "Max APIC IDs reserved field is valid", "CPUID.1.0:EDX.HTT[bit 28]", "common", "", -1},
+			29: {"TM", "Thermal Monitor", "CPUID.1.0:EDX.TM[bit 29]", "intel", "", -1},
+			30: {"IA64", "IA64 processor emulating x86", "CPUID.1.0:EDX.IA64[bit 30]", "intel", "", -1},
+			31: {"PBE", "Pending Break Enable", "CPUID.1.0:EDX.PBE[bit 31]", "intel", "", -1},
+		},
+	},
+
+	"ExtendedEBX": {
+		name:     "Extended Features EBX",
+		leaf:     7,
+		subleaf:  0,
+		register: 1,
+		group:    "Basic CPU",
+		features: map[int]Feature{
+			0:  {"FSGSBASE", "RDFSBASE/RDGSBASE/WRFSBASE/WRGSBASE instructions", "CPUID.7.0:EBX.FSGSBASE[bit 0]", "common", "", -1},
+			1:  {"TSC_ADJUST", "IA32_TSC_ADJUST MSR", "CPUID.7.0:EBX.TSC_ADJUST[bit 1]", "intel", "", -1},
+			2:  {"SGX", "Software Guard Extensions", "CPUID.7.0:EBX.SGX[bit 2]", "intel", "", -1},
+			3:  {"BMI1", "Bit Manipulation Instruction Set 1", "CPUID.7.0:EBX.BMI1[bit 3]", "common", "", -1},
+			4:  {"HLE", "Hardware Lock Elision", "CPUID.7.0:EBX.HLE[bit 4]", "intel", "", -1},
+			5:  {"AVX2", "Advanced Vector Extensions 2", "CPUID.7.0:EBX.AVX2[bit 5]", "common", "", -1},
+			6:  {"FDP_EXCPTN_ONLY", "x87 FPU data pointer updated only on exceptions", "CPUID.7.0:EBX.FDP_EXCPTN_ONLY[bit 6]", "intel", "", -1},
+			7:  {"SMEP", "Supervisor Mode Execution Prevention", "CPUID.7.0:EBX.SMEP[bit 7]", "common", "", -1},
+			8:  {"BMI2", "Bit Manipulation Instruction Set 2", "CPUID.7.0:EBX.BMI2[bit 8]", "common", "", -1},
+			9:  {"ERMS", "Enhanced REP MOVSB/STOSB", "CPUID.7.0:EBX.ERMS[bit 9]", "common", "", -1},
+			10: {"INVPCID", "INVPCID instruction", "CPUID.7.0:EBX.INVPCID[bit 10]", "common", "", -1},
+			11: {"RTM", "Restricted Transactional Memory", "CPUID.7.0:EBX.RTM[bit 11]", "intel", "", -1},
+			12: {"PQM", "Platform Quality of Service Monitoring", "CPUID.7.0:EBX.PQM[bit 12]", "intel", "", -1},
+			14: {"MPX", "Memory Protection Extensions", "CPUID.7.0:EBX.MPX[bit 14]", "intel", "", -1},
+			15: {"PQE", "Platform Quality of Service Enforcement", "CPUID.7.0:EBX.PQE[bit 15]", "intel", "", -1},
+			16: {"AVX512F", "AVX-512 Foundation", "CPUID.7.0:EBX.AVX512F[bit 16]", "intel", "", -1},
+			17: {"AVX512DQ", "AVX-512 Doubleword and Quadword Instructions", "CPUID.7.0:EBX.AVX512DQ[bit 17]", "intel", "", -1},
+			18: {"RDSEED", "RDSEED instruction", "CPUID.7.0:EBX.RDSEED[bit 18]", "common", "", -1},
+			19: {"ADX", "Multi-Precision Add-Carry instructions", "CPUID.7.0:EBX.ADX[bit 19]", "common", "", -1},
+			20: {"SMAP", "Supervisor Mode Access Prevention", "CPUID.7.0:EBX.SMAP[bit 20]", "common", "", -1},
+			21: {"AVX512IFMA", "AVX-512 Integer Fused Multiply-Add Instructions", "CPUID.7.0:EBX.AVX512IFMA[bit 21]", "intel", "", -1},
+			23: {"CLFLUSHOPT", "CLFLUSHOPT instruction", "CPUID.7.0:EBX.CLFLUSHOPT[bit 23]", "common", "", -1},
+			24: {"CLWB", "CLWB instruction", "CPUID.7.0:EBX.CLWB[bit 24]", "common", "", -1},
+			25: {"PT", "Intel Processor Trace", "CPUID.7.0:EBX.PT[bit 25]", "intel", "", -1},
+			26: {"AVX512PF", "AVX-512 Prefetch Instructions", "CPUID.7.0:EBX.AVX512PF[bit 26]", "intel", "", -1},
+			27: {"AVX512ER", "AVX-512 Exponential and Reciprocal Instructions", "CPUID.7.0:EBX.AVX512ER[bit 27]", "intel", "", -1},
+			28: {"AVX512CD", "AVX-512 Conflict Detection Instructions", "CPUID.7.0:EBX.AVX512CD[bit 28]", "intel", "", -1},
+			29: {"SHA", "SHA extensions", "CPUID.7.0:EBX.SHA[bit 29]", "common", "", -1},
+			30: {"AVX512BW", "AVX-512 Byte and Word Instructions", "CPUID.7.0:EBX.AVX512BW[bit 30]", "intel", "", -1},
+			31: {"AVX512VL", "AVX-512 Vector Length Extensions", "CPUID.7.0:EBX.AVX512VL[bit 31]", "intel", "", -1},
+		},
+	},
+
+	"ExtendedECX": {
+		name:     "Extended Features ECX",
+		leaf:     7,
+		subleaf:  0,
+		register: 2,
+		group:    "Basic CPU",
+		features: map[int]Feature{
+			0:  {"PREFETCHWT1", "PREFETCHWT1 instruction", "CPUID.7.0:ECX.PREFETCHWT1[bit 0]", "intel", "", -1},
+			1:  {"AVX512_VBMI", "AVX-512 Vector Byte Manipulation Instructions", "CPUID.7.0:ECX.AVX512_VBMI[bit 1]", "intel", "", -1},
+			2:  {"UMIP", "User-Mode Instruction Prevention", "CPUID.7.0:ECX.UMIP[bit 2]", "common", "", -1},
+			3:  {"PKU", "Protection Keys for user-mode pages", "CPUID.7.0:ECX.PKU[bit 3]", "common", "", -1},
+			4:  {"OSPKE", "PKU enabled by OS", "CPUID.7.0:ECX.OSPKE[bit 4]", "common", "", -1},
+			5:  {"WAITPKG", "UMONITOR/UMWAIT/TPAUSE instructions", "CPUID.7.0:ECX.WAITPKG[bit 5]", "intel", "", -1},
+			6:  {"AVX512_VBMI2", "AVX-512 Vector Byte Manipulation Instructions 2", "CPUID.7.0:ECX.AVX512_VBMI2[bit 6]", "intel", "", -1},
+			7:  {"CET_SS", "Control Flow Enforcement Shadow Stack", "CPUID.7.0:ECX.CET_SS[bit 7]", "common", "", -1},
+			8:  {"GFNI", "Galois Field instructions", "CPUID.7.0:ECX.GFNI[bit 8]", "common", "", -1},
+			9:  {"VAES", "Vector AES instruction set", "CPUID.7.0:ECX.VAES[bit 9]", "common", "", -1},
+			10: {"VPCLMULQDQ", "Vector carry-less multiplication instructions", "CPUID.7.0:ECX.VPCLMULQDQ[bit 10]", "common", "", -1},
+			11: {"AVX512_VNNI", "AVX-512 Vector Neural Network Instructions", "CPUID.7.0:ECX.AVX512_VNNI[bit 11]", "intel", "", -1},
+			12: {"AVX512_BITALG", "AVX-512 Bit Algorithms", "CPUID.7.0:ECX.AVX512_BITALG[bit 12]", "intel", "", -1},
+			13: {"TME_EN", "Total Memory Encryption enabled", "CPUID.7.0:ECX.TME_EN[bit 13]", "intel", "", -1},
+			14: {"AVX512_VPOPCNTDQ", "AVX-512 Vector Population Count Doubleword and Quadword", "CPUID.7.0:ECX.AVX512_VPOPCNTDQ[bit 14]", "intel", "", -1},
+			16: {"LA57", "5-level paging", "CPUID.7.0:ECX.LA57[bit 16]", "common", "", -1},
+			22: {"RDPID", "Read Processor ID instruction", "CPUID.7.0:ECX.RDPID[bit 22]", "common", "", -1},
+			23: {"KL", "Key Locker", "CPUID.7.0:ECX.KL[bit 23]", "intel", "", -1},
+			25: {"CLDEMOTE", "Cache line demote instruction", "CPUID.7.0:ECX.CLDEMOTE[bit 25]", "intel", "", -1},
+			27: {"MOVDIRI", "MOVDIRI instruction", "CPUID.7.0:ECX.MOVDIRI[bit 27]", "common", "", -1},
+			28: {"MOVDIR64B", "MOVDIR64B instruction", "CPUID.7.0:ECX.MOVDIR64B[bit 28]", "common", "", -1},
+			29: {"ENQCMD", "Enqueue Command instructions", "CPUID.7.0:ECX.ENQCMD[bit 29]", "intel", "", -1},
+			30: {"SGX_LC", "SGX Launch Configuration", "CPUID.7.0:ECX.SGX_LC[bit 30]", "intel", "", -1},
+			31: {"PKS", "Protection Keys for supervisor-mode pages", "CPUID.7.0:ECX.PKS[bit 31]", "intel", "", -1},
+		},
+	},
+}
@@ -0,0 +1,188 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "encoding/json"
+
+// In identifies a CPUID leaf/subleaf pair to query or override.
+type In struct {
+	Leaf    uint32
+	Subleaf uint32
+}
+
+// Out holds the four CPUID output registers for an In.
+type Out struct {
+	EAX, EBX, ECX, EDX uint32
+}
+
+// CPUIDOverlay is a programmable overlay over raw CPUID results. It lets
+// callers force individual feature bits on or off before any of the
+// Get*/IsFeatureSupported helpers read them, which is what's needed to
+// compute a "minimum common denominator" CPUID for VM live-migration or CI
+// matrices, the way gVisor's CPUID masking does.
+type CPUIDOverlay struct {
+	base      Data
+	overrides map[In]Out
+}
+
+// NewCPUIDOverlay builds a CPUIDOverlay from a captured Data snapshot (see
+// CaptureData/DataFromFile). The overlay starts empty, so Query behaves
+// exactly like reading base until Set is called.
+func NewCPUIDOverlay(base Data) *CPUIDOverlay {
+	return &CPUIDOverlay{base: base, overrides: make(map[In]Out)}
+}
+
+// entryLocation returns the In{Leaf,Subleaf} and register index that a named
+// feature lives at, consulting cpuFeaturesList the same way
+// GetSupportedFeatures/IsFeatureSupported do.
+//
+// One edge case matters here: features that live in the xsave subleaf (leaf
+// 0xD, subleaf 1 -- XSAVEC, XGETBV1) must resolve to In{Eax: 0xD, Ecx: 1},
+// not subleaf 0, or toggling them silently no-ops.
+func entryLocation(featureName string) (In, int, bool) {
+	for _, fs := range cpuFeaturesList {
+		for _, f := range fs.features {
+			if f.name == featureName {
+				return In{Leaf: fs.leaf, Subleaf: fs.subleaf}, fs.register, true
+			}
+		}
+	}
+	return In{}, 0, false
+}
+
+// bitOf returns the bit index of featureName within its cpuFeaturesList
+// FeatureSet, or -1.
+func bitOf(featureName string) int {
+	for _, fs := range cpuFeaturesList {
+		for bit, f := range fs.features {
+			if f.name == featureName {
+				return bit
+			}
+		}
+	}
+	return -1
+}
+
+// Query returns the (possibly overridden) register values for in, consulting
+// the overlay first and falling back to the base snapshot.
+func (fset *CPUIDOverlay) Query(in In) Out {
+	if out, ok := fset.overrides[in]; ok {
+		return out
+	}
+
+	for _, e := range fset.base.Entries {
+		if e.Leaf == in.Leaf && e.Subleaf == in.Subleaf {
+			return Out{EAX: e.EAX, EBX: e.EBX, ECX: e.ECX, EDX: e.EDX}
+		}
+	}
+	return Out{}
+}
+
+// Set forces the named feature bit on or off in the overlay, reading the
+// current value (from the overlay if already touched, otherwise the base
+// snapshot) and only flipping the one bit so neighbouring features at the
+// same leaf/register are preserved.
+func (fset *CPUIDOverlay) Set(feature string, on bool) {
+	in, register, ok := entryLocation(feature)
+	if !ok {
+		return
+	}
+	bit := bitOf(feature)
+	if bit < 0 {
+		return
+	}
+
+	out := fset.Query(in)
+	reg := registerPointer(&out, register)
+	if on {
+		*reg |= 1 << uint(bit)
+	} else {
+		*reg &^= 1 << uint(bit)
+	}
+	fset.overrides[in] = out
+}
+
+// registerPointer returns a pointer to the register of out selected by
+// index, matching the 0=EAX,1=EBX,2=ECX,3=EDX convention used throughout
+// cpuFeaturesList.
+func registerPointer(out *Out, index int) *uint32 {
+	switch index {
+	case 0:
+		return &out.EAX
+	case 1:
+		return &out.EBX
+	case 2:
+		return &out.ECX
+	default:
+		return &out.EDX
+	}
+}
+
+// Mask returns a new CPUIDOverlay that is the intersection of fset and other:
+// for every leaf/subleaf either has touched, each bit is set only if it's
+// set in both. This is the "safe baseline across a fleet" operation --
+// computing the CPUID a VM could be migrated to/from either host without
+// exposing a feature the destination lacks.
+func (fset *CPUIDOverlay) Mask(other *CPUIDOverlay) *CPUIDOverlay {
+	result := NewCPUIDOverlay(fset.base)
+
+	seen := make(map[In]bool)
+	for in := range fset.overrides {
+		seen[in] = true
+	}
+	for in := range other.overrides {
+		seen[in] = true
+	}
+	for _, e := range fset.base.Entries {
+		seen[In{Leaf: e.Leaf, Subleaf: e.Subleaf}] = true
+	}
+
+	for in := range seen {
+		a := fset.Query(in)
+		b := other.Query(in)
+		result.overrides[in] = Out{
+			EAX: a.EAX & b.EAX,
+			EBX: a.EBX & b.EBX,
+			ECX: a.ECX & b.ECX,
+			EDX: a.EDX & b.EDX,
+		}
+	}
+
+	return result
+}
+
+// featureSetJSON is the on-the-wire representation of a CPUIDOverlay: the base
+// snapshot plus a flat list of overrides, since map[In]Out doesn't marshal
+// directly (In is a struct key).
+type featureSetJSON struct {
+	Base      Data                  `json:"base"`
+	Overrides []featureOverrideJSON `json:"overrides"`
+}
+
+type featureOverrideJSON struct {
+	In  In  `json:"in"`
+	Out Out `json:"out"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (fset *CPUIDOverlay) MarshalJSON() ([]byte, error) {
+	doc := featureSetJSON{Base: fset.base}
+	for in, out := range fset.overrides {
+		doc.Overrides = append(doc.Overrides, featureOverrideJSON{In: in, Out: out})
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (fset *CPUIDOverlay) UnmarshalJSON(data []byte) error {
+	var doc featureSetJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	fset.base = doc.Base
+	fset.overrides = make(map[In]Out, len(doc.Overrides))
+	for _, o := range doc.Overrides {
+		fset.overrides[o.In] = o.Out
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"math/bits"
+	"runtime"
+)
+
+// LogicalCPU is one logical processor's full topology placement: its
+// x2APIC ID decoded into the package/die/module/core/thread hierarchy (see
+// Topology.Decode), Intel's hybrid core type/efficiency class/native model
+// ID (CPUID.1AH), AMD's compute-unit/node addressing (CPUID.8000001EH), and
+// which other logical CPUs share each cache level with it.
+type LogicalCPU struct {
+	CPU       int
+	APICID    uint32
+	PackageID uint32
+	DieID     uint32
+	ModuleID  uint32
+	CoreID    uint32
+	ThreadID  uint32
+
+	// CoreType is "P", "E", "LP-E" on a hybrid Intel CPU, or "" on a
+	// homogeneous one.
+	CoreType        string
+	EfficiencyClass uint32
+	NativeModelID   uint32
+
+	// ComputeUnitID and NodeID are AMD's CPUID.8000001EH addressing; both
+	// are 0 on Intel.
+	ComputeUnitID uint32
+	NodeID        uint32
+
+	// L1Sharing, L2Sharing, L3Sharing list every logical CPU index
+	// (including this one) that shares the corresponding cache level, per
+	// CPUID leaf 4/0x8000001D's MaxCoresSharing field.
+	L1Sharing []int
+	L2Sharing []int
+	L3Sharing []int
+}
+
+// intelCoreType decodes CPUID.1AH:EAX's native-model-ID/core-type fields for
+// the calling logical CPU, matching CaptureAllCPUs' "P"/"E" convention and
+// adding the "LP-E" (Low Power Efficiency core, e.g. Meteor Lake's
+// E-core-on-SoC-tile) and EfficiencyClass/NativeModelID detail CaptureAllCPUs
+// doesn't capture.
+func intelCoreType() (coreType string, efficiencyClass, nativeModelID uint32) {
+	a, _, _, _ := cpuid(0x1A, 0)
+	hybridType := (a >> 24) & 0xFF
+	if hybridType == 0 {
+		return "", 0, 0
+	}
+
+	nativeModelID = a & 0xFFFFFF
+	efficiencyClass = (a >> 8) & 0xFF
+
+	switch hybridType {
+	case 0x20:
+		coreType = "E"
+	case 0x40:
+		coreType = "P"
+	case 0x30:
+		coreType = "LP-E"
+	default:
+		coreType = "Unknown"
+	}
+	return coreType, efficiencyClass, nativeModelID
+}
+
+// EnumerateLogicalCPUs returns the fully decoded per-logical-CPU topology for every
+// online processor, pinning a goroutine to each one in turn (see
+// captureOnCPU) so hybrid Intel P/E placement and AMD compute-unit/node IDs
+// are read from the CPU they actually describe rather than wherever the
+// calling goroutine happens to land.
+func EnumerateLogicalCPUs() ([]LogicalCPU, error) {
+	topo, err := EnumerateTopology()
+	if err != nil {
+		return nil, err
+	}
+
+	amd := isAMD(false, "")
+	maxFunc, maxExtFunc := GetMaxFunctions(false, "")
+	caches, _ := GetCacheInfo(maxFunc, maxExtFunc, GetVendorID(false, ""), false, "")
+
+	n := runtime.NumCPU()
+	cpus := make([]LogicalCPU, n)
+
+	for cpu := 0; cpu < n; cpu++ {
+		lc, err := logicalCPUOn(cpu, topo, amd)
+		if err != nil {
+			return nil, err
+		}
+		cpus[cpu] = lc
+	}
+
+	attachCacheSharing(cpus, caches)
+	return cpus, nil
+}
+
+// logicalCPUOn pins to cpu and reads its APIC ID, hybrid core type, and AMD
+// compute-unit/node addressing.
+func logicalCPUOn(cpu int, topo *Topology, amd bool) (LogicalCPU, error) {
+	done := make(chan struct{})
+	var lc LogicalCPU
+	var pinErr error
+
+	go func() {
+		defer close(done)
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		if err := lockToCPU(cpu); err != nil {
+			pinErr = err
+			return
+		}
+
+		var apicID uint32
+		_, _, c, d := cpuid(0xB, 0)
+		if c != 0 {
+			apicID = d
+		}
+
+		lc = LogicalCPU{CPU: cpu, APICID: apicID}
+		coords := topo.Decode(apicID)
+		lc.PackageID = coords.Socket
+		lc.DieID = coords.Die
+		lc.ModuleID = coords.Module
+		lc.CoreID = coords.Core
+		lc.ThreadID = coords.Thread
+
+		lc.CoreType, lc.EfficiencyClass, lc.NativeModelID = intelCoreType()
+
+		if amd {
+			_, b, c, _ := cpuid(0x8000001E, 0)
+			lc.ComputeUnitID = b & 0xFF
+			lc.NodeID = c & 0xFF
+		}
+	}()
+	<-done
+
+	return lc, pinErr
+}
+
+// attachCacheSharing fills in L1Sharing/L2Sharing/L3Sharing for every entry
+// in cpus, grouping logical CPUs whose APIC ID matches above the bits a
+// cache level's MaxCoresSharing needs -- the same masking scheme the SDM
+// describes for deriving a cache's sharing domain from x2APIC IDs.
+func attachCacheSharing(cpus []LogicalCPU, caches []CPUCacheInfo) {
+	for _, cache := range caches {
+		shift := bits.Len32(cache.MaxCoresSharing - 1)
+		groups := make(map[uint32][]int)
+		for i, lc := range cpus {
+			key := lc.APICID >> uint(shift)
+			groups[key] = append(groups[key], i)
+		}
+
+		for i, lc := range cpus {
+			sharing := groups[lc.APICID>>uint(shift)]
+			switch cache.Level {
+			case 1:
+				cpus[i].L1Sharing = sharing
+			case 2:
+				cpus[i].L2Sharing = sharing
+			case 3:
+				cpus[i].L3Sharing = sharing
+			}
+		}
+	}
+}
+
+// HybridPartition splits EnumerateLogicalCPUs()'s result into separate
+// P-core and E-core logical CPU index sets, so a scheduler or benchmark
+// harness can pin work to one class with e.g. runtime.LockOSThread + lockToCPU.
+// E-core here covers both "E" and "LP-E" CoreTypes; a homogeneous (non-
+// hybrid) CPU reports every CPU as P-cores, matching the convention that an
+// unset CoreType is architecturally "the only core type there is".
+func HybridPartition() (pCores, eCores []int, err error) {
+	cpus, err := EnumerateLogicalCPUs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, lc := range cpus {
+		switch lc.CoreType {
+		case "E", "LP-E":
+			eCores = append(eCores, lc.CPU)
+		default:
+			pCores = append(pCores, lc.CPU)
+		}
+	}
+	return pCores, eCores, nil
+}
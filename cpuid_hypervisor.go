@@ -0,0 +1,170 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// HypervisorVendor identifies the hypervisor signature reported in CPUID
+// leaf 0x40000000:EBX/ECX/EDX, mirroring the vendor strings KVM, Hyper-V,
+// Xen, VMware, and QEMU's own TCG software emulation each advertise to a
+// guest.
+type HypervisorVendor string
+
+// Known hypervisor vendors, keyed by the 12-byte signature string they
+// advertise at CPUID leaf 0x40000000.
+const (
+	HypervisorKVM     HypervisorVendor = "KVM"
+	HypervisorHyperV  HypervisorVendor = "Hyper-V"
+	HypervisorXen     HypervisorVendor = "Xen"
+	HypervisorVMware  HypervisorVendor = "VMware"
+	HypervisorQEMUTCG HypervisorVendor = "QEMU TCG"
+	HypervisorUnknown HypervisorVendor = "Unknown"
+)
+
+// hypervisorSignatures maps the leaf 0x40000000 vendor string to the
+// vendor it identifies. KVM under real hardware acceleration and QEMU's
+// software emulation (TCG) both set the HYPERVISOR bit but advertise
+// different signatures, so callers can tell "guest under real KVM" apart
+// from "guest under plain QEMU emulation".
+var hypervisorSignatures = map[string]HypervisorVendor{
+	"KVMKVMKVM\x00\x00\x00": HypervisorKVM,
+	"TCGTCGTCGTCG":          HypervisorQEMUTCG,
+	"Microsoft Hv":          HypervisorHyperV,
+	"XenVMMXenVMM":          HypervisorXen,
+	"VMwareVMware":          HypervisorVMware,
+}
+
+// HypervisorInfo is the result of probing CPUID.1:ECX[31] (the HYPERVISOR
+// bit) and, if it's set, the vendor signature at leaf 0x40000000.
+type HypervisorInfo struct {
+	Present   bool             `json:"present"`
+	Vendor    HypervisorVendor `json:"vendor,omitempty"`
+	Signature string           `json:"signature,omitempty"`
+	MaxLeaf   uint32           `json:"max_leaf,omitempty"`
+}
+
+// GetHypervisorInfo reports whether the running/captured CPU is presenting
+// itself as a hypervisor guest, per the offline/filename pair every other
+// Get*Info helper in this package accepts.
+func GetHypervisorInfo(offline bool, filename string) HypervisorInfo {
+	src, err := sourceFromMode(offline, filename)
+	if err != nil {
+		return HypervisorInfo{}
+	}
+	return GetHypervisorInfoFromSource(src)
+}
+
+// GetHypervisorInfoFromSource is the Source-based sibling of
+// GetHypervisorInfo.
+func GetHypervisorInfoFromSource(src Source) HypervisorInfo {
+	_, _, c, _ := src.CPUID(1, 0)
+	if (c>>31)&1 == 0 {
+		return HypervisorInfo{}
+	}
+
+	maxLeaf, b, c2, d := src.CPUID(0x40000000, 0)
+	sig := hypervisorSignature(b, c2, d)
+
+	vendor, ok := hypervisorSignatures[sig]
+	if !ok {
+		vendor = HypervisorUnknown
+	}
+
+	return HypervisorInfo{
+		Present:   true,
+		Vendor:    vendor,
+		Signature: sig,
+		MaxLeaf:   maxLeaf,
+	}
+}
+
+// hypervisorSignature decodes the 12-byte vendor string CPUID leaf
+// 0x40000000 packs into EBX:ECX:EDX, the same little-endian layout leaf 0's
+// EBX:EDX:ECX uses for the CPU vendor ID.
+func hypervisorSignature(b, c, d uint32) string {
+	buf := make([]byte, 0, 12)
+	for _, reg := range [3]uint32{b, c, d} {
+		buf = append(buf, byte(reg), byte(reg>>8), byte(reg>>16), byte(reg>>24))
+	}
+	return string(buf)
+}
+
+// Virtualized is the package-level query a caller runs before deciding
+// whether a HypervisorProfile is even meaningful: ok is false on bare
+// metal, in which case info is the zero HypervisorInfo.
+func Virtualized(offline bool, filename string) (info HypervisorInfo, ok bool) {
+	info = GetHypervisorInfo(offline, filename)
+	return info, info.Present
+}
+
+// FeatureExposure describes how a hypervisor is presenting a single
+// capability to the guest: advertised untouched from the host, withheld
+// entirely, or (in the future, as more of these are identified) synthesized
+// by the hypervisor itself rather than passed through from real silicon.
+type FeatureExposure string
+
+// The three exposure states a hypervisor can put a guest-visible capability
+// in.
+const (
+	Exposed            FeatureExposure = "exposed"
+	Emulated           FeatureExposure = "emulated"
+	HiddenByHypervisor FeatureExposure = "hidden-by-hypervisor"
+)
+
+// HypervisorProfile summarizes the handful of guest-visible capabilities
+// that actually change meaning under virtualization -- nested
+// virtualization extensions, invariant TSC, and (on AMD) SEV-SNP -- the
+// same distinctions QEMU has to reason about when constructing a guest's
+// CPUID from a host's.
+type HypervisorProfile struct {
+	Vendor       HypervisorVendor `json:"vendor"`
+	NestedVMX    FeatureExposure  `json:"nested_vmx,omitempty"`
+	NestedSVM    FeatureExposure  `json:"nested_svm,omitempty"`
+	InvariantTSC FeatureExposure  `json:"invariant_tsc"`
+	SEVSNP       FeatureExposure  `json:"sev_snp,omitempty"`
+	Notes        []string         `json:"notes,omitempty"`
+}
+
+// BuildHypervisorProfile inspects the nested-virtualization, invariant-TSC,
+// and SEV-SNP bits directly (leaves 1, 0x80000001, 0x80000007, 0x8000001F)
+// and classifies each as Exposed or HiddenByHypervisor relative to info. It
+// returns the zero HypervisorProfile when info.Present is false, since
+// these distinctions are meaningless on bare metal.
+func BuildHypervisorProfile(info HypervisorInfo, src Source) HypervisorProfile {
+	if !info.Present {
+		return HypervisorProfile{}
+	}
+
+	profile := HypervisorProfile{Vendor: info.Vendor}
+
+	_, _, c1, _ := src.CPUID(1, 0)
+	if (c1>>5)&1 == 1 {
+		profile.NestedVMX = Exposed
+		profile.Notes = append(profile.Notes, "VMX nested virtualization exposed to guest")
+	} else {
+		profile.NestedVMX = HiddenByHypervisor
+	}
+
+	_, _, c81, _ := src.CPUID(0x80000001, 0)
+	if (c81>>2)&1 == 1 {
+		profile.NestedSVM = Exposed
+		profile.Notes = append(profile.Notes, "SVM nested virtualization exposed to guest")
+	} else {
+		profile.NestedSVM = HiddenByHypervisor
+	}
+
+	_, _, _, d807 := src.CPUID(0x80000007, 0)
+	if (d807>>8)&1 == 1 {
+		profile.InvariantTSC = Exposed
+		profile.Notes = append(profile.Notes, "invariant TSC advertised by host")
+	} else {
+		profile.InvariantTSC = HiddenByHypervisor
+	}
+
+	a801f, _, _, _ := src.CPUID(0x8000001F, 0)
+	if (a801f>>4)&1 == 1 {
+		profile.SEVSNP = Exposed
+		profile.Notes = append(profile.Notes, "SEV-SNP passthrough available")
+	} else if info.Vendor == HypervisorKVM {
+		profile.SEVSNP = HiddenByHypervisor
+	}
+
+	return profile
+}
@@ -53,6 +53,32 @@ func GetBrandString(maxExtFunc uint32, offline bool, filename string) string {
 	return ""
 }
 
+// ProcessorModel is the family/model/stepping triple CPUID.1H:EAX decodes
+// into, along with the effective family/model the SDM derives from it once
+// the extended fields are folded in (see GetModelData).
+type ProcessorModel struct {
+	SteppingID      uint32
+	ModelID         uint32
+	FamilyID        uint32
+	ProcessorType   uint32
+	ExtendedModel   uint32
+	ExtendedFamily  uint32
+	EffectiveModel  uint32
+	EffectiveFamily uint32
+}
+
+// ProcessorInfo is the core/thread topology and address-width detail
+// GetProcessorInfo derives from CPUID leaf 1, leaf 4/0xB, and
+// 0x80000008/0x8000001E.
+type ProcessorInfo struct {
+	MaxLogicalProcessors uint32
+	InitialAPICID        uint32
+	PhysicalAddressBits  uint32
+	LinearAddressBits    uint32
+	CoreCount            uint32
+	ThreadPerCore        uint32
+}
+
 // GetModelData contains information about the processor model.
 func GetModelData(offline bool, filename string) ProcessorModel {
 	// Get Model Data
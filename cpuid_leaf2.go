@@ -0,0 +1,226 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// descriptorKind distinguishes what a leaf-2 descriptor byte decodes to.
+type descriptorKind int
+
+const (
+	descriptorNull descriptorKind = iota
+	descriptorCache
+	descriptorTLB
+	descriptorPrefetch
+	descriptorUseLeaf4
+	descriptorUseLeaf18
+)
+
+// leaf2Descriptor is one entry of Intel SDM Vol.2 Table 3-12.
+type leaf2Descriptor struct {
+	kind  descriptorKind
+	cache CPUCacheInfo
+	tlb   TLBEntry
+	// tlbLevel/tlbType classify where a TLB descriptor belongs.
+	tlbLevel int    // 1, 2 or 3
+	tlbType  string // "Data", "Instruction" or "Unified"
+	// note carries the prefetcher description for descriptorPrefetch
+	// entries, which don't fit the cache/TLB shape.
+	note string
+}
+
+// leaf2Descriptors maps the descriptor byte (as returned in CPUID.02H) to its
+// meaning, covering the common TLB and cache entries from SDM Vol.2 Table
+// 3-12. 0x00 (null), 0xF0/0xF1 (prefetch), 0xFE ("use leaf 0x18") and 0xFF
+// ("use leaf 4") are stored here too, each with its own descriptor kind,
+// which DecodeIntelLeaf2 acts on instead of decoding it as a cache or TLB.
+var leaf2Descriptors = map[byte]leaf2Descriptor{
+	0x01: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB", Entries: 32, Associativity: "4-way"}},
+	0x02: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4MB", Entries: 2, Associativity: "Fully associative"}},
+	0x03: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB", Entries: 64, Associativity: "4-way"}},
+	0x04: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4MB", Entries: 8, Associativity: "4-way"}},
+	0x05: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4MB", Entries: 32, Associativity: "4-way"}},
+	0x0B: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4MB", Entries: 4, Associativity: "4-way"}},
+	0x4F: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB", Entries: 32, Associativity: "Unknown"}},
+	0x50: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB/2MB/4MB", Entries: 64, Associativity: "Fully associative"}},
+	0x51: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB/2MB/4MB", Entries: 128, Associativity: "Fully associative"}},
+	0x52: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB/2MB/4MB", Entries: 256, Associativity: "Fully associative"}},
+	0x55: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "2MB/4MB", Entries: 7, Associativity: "Fully associative"}},
+	0x56: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4MB", Entries: 16, Associativity: "4-way"}},
+	0x57: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB", Entries: 16, Associativity: "4-way"}},
+	0x59: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB", Entries: 16, Associativity: "Fully associative"}},
+	0x5A: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "2MB/4MB", Entries: 32, Associativity: "4-way"}},
+	0x5B: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB/4MB", Entries: 64, Associativity: "Fully associative"}},
+	0x5C: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB/4MB", Entries: 128, Associativity: "Fully associative"}},
+	0x5D: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB/4MB", Entries: 256, Associativity: "Fully associative"}},
+
+	0x06: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Instruction", SizeKB: 8, Ways: 4, LineSizeBytes: 32}},
+	0x08: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Instruction", SizeKB: 16, Ways: 4, LineSizeBytes: 32}},
+	0x09: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Instruction", SizeKB: 32, Ways: 4, LineSizeBytes: 64}},
+	0x0A: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 8, Ways: 2, LineSizeBytes: 32}},
+	0x0C: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 16, Ways: 4, LineSizeBytes: 32}},
+	0x0D: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 16, Ways: 4, LineSizeBytes: 64}},
+	0x0E: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 24, Ways: 6, LineSizeBytes: 64}},
+	0x21: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 256, Ways: 8, LineSizeBytes: 64}},
+	0x22: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 512, Ways: 4, LineSizeBytes: 64}},
+	0x23: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 1024, Ways: 8, LineSizeBytes: 64}},
+	0x25: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 2048, Ways: 8, LineSizeBytes: 64}},
+	0x29: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 4096, Ways: 8, LineSizeBytes: 64}},
+	0x2C: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 32, Ways: 8, LineSizeBytes: 64}},
+	0x30: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Instruction", SizeKB: 32, Ways: 8, LineSizeBytes: 64}},
+	0x40: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 0, Ways: 0, LineSizeBytes: 0}}, // no L2/L3 present
+	0x41: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 128, Ways: 4, LineSizeBytes: 32}},
+	0x42: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 256, Ways: 4, LineSizeBytes: 32}},
+	0x43: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 512, Ways: 4, LineSizeBytes: 32}},
+	0x44: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 1024, Ways: 4, LineSizeBytes: 32}},
+	0x45: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 2048, Ways: 4, LineSizeBytes: 32}},
+	0x46: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 4096, Ways: 4, LineSizeBytes: 64}},
+	0x47: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 8192, Ways: 8, LineSizeBytes: 64}},
+	0x48: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 3072, Ways: 12, LineSizeBytes: 64}},
+	0x49: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 4096, Ways: 16, LineSizeBytes: 64}},
+	0x4A: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 6144, Ways: 12, LineSizeBytes: 64}},
+	0x4B: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 8192, Ways: 16, LineSizeBytes: 64}},
+	0x4C: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 12288, Ways: 12, LineSizeBytes: 64}},
+	0x4D: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 16384, Ways: 16, LineSizeBytes: 64}},
+	0x4E: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 6144, Ways: 24, LineSizeBytes: 64}},
+	0x60: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 16, Ways: 8, LineSizeBytes: 64}},
+	0x66: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 8, Ways: 4, LineSizeBytes: 64}},
+	0x67: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 16, Ways: 4, LineSizeBytes: 64}},
+	0x68: {kind: descriptorCache, cache: CPUCacheInfo{Level: 1, Type: "Data", SizeKB: 32, Ways: 4, LineSizeBytes: 64}},
+	0x78: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 1024, Ways: 4, LineSizeBytes: 64}},
+	0x79: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 128, Ways: 8, LineSizeBytes: 64}},
+	0x7A: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 256, Ways: 8, LineSizeBytes: 64}},
+	0x7B: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 512, Ways: 8, LineSizeBytes: 64}},
+	0x7C: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 1024, Ways: 8, LineSizeBytes: 64}},
+	0x7D: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 2048, Ways: 8, LineSizeBytes: 64}},
+	0x7F: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 512, Ways: 2, LineSizeBytes: 64}},
+	0x80: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 512, Ways: 8, LineSizeBytes: 64}},
+	0x82: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 256, Ways: 8, LineSizeBytes: 32}},
+	0x83: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 512, Ways: 8, LineSizeBytes: 32}},
+	0x84: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 1024, Ways: 8, LineSizeBytes: 32}},
+	0x85: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 2048, Ways: 8, LineSizeBytes: 32}},
+	0x86: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 512, Ways: 4, LineSizeBytes: 64}},
+	0x87: {kind: descriptorCache, cache: CPUCacheInfo{Level: 2, Type: "Unified", SizeKB: 1024, Ways: 8, LineSizeBytes: 64}},
+	0xD0: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 512, Ways: 4, LineSizeBytes: 64}},
+	0xD1: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 1024, Ways: 4, LineSizeBytes: 64}},
+	0xD2: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 2048, Ways: 4, LineSizeBytes: 64}},
+	0xD6: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 1024, Ways: 8, LineSizeBytes: 64}},
+	0xD7: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 2048, Ways: 8, LineSizeBytes: 64}},
+	0xD8: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 4096, Ways: 8, LineSizeBytes: 64}},
+	0xDC: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 1536, Ways: 12, LineSizeBytes: 64}},
+	0xDD: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 3072, Ways: 12, LineSizeBytes: 64}},
+	0xDE: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 6144, Ways: 12, LineSizeBytes: 64}},
+	0xE2: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 2048, Ways: 16, LineSizeBytes: 64}},
+	0xE3: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 4096, Ways: 16, LineSizeBytes: 64}},
+	0xE4: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 8192, Ways: 16, LineSizeBytes: 64}},
+	0xEA: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 12288, Ways: 24, LineSizeBytes: 64}},
+	0xEB: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 18432, Ways: 24, LineSizeBytes: 64}},
+	0xEC: {kind: descriptorCache, cache: CPUCacheInfo{Level: 3, Type: "Unified", SizeKB: 24576, Ways: 24, LineSizeBytes: 64}},
+
+	0xB0: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB", Entries: 128, Associativity: "4-way"}},
+	0xB1: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "2MB/4MB", Entries: 8, Associativity: "4-way"}},
+	0xB2: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB", Entries: 64, Associativity: "4-way"}},
+	0xB3: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB", Entries: 128, Associativity: "4-way"}},
+	0xB4: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB", Entries: 256, Associativity: "4-way"}},
+	0xB5: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB", Entries: 64, Associativity: "8-way"}},
+	0xB6: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Instruction", tlb: TLBEntry{PageSize: "4KB", Entries: 128, Associativity: "8-way"}},
+	0xBA: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB", Entries: 64, Associativity: "4-way"}},
+
+	0xC0: {kind: descriptorTLB, tlbLevel: 1, tlbType: "Data", tlb: TLBEntry{PageSize: "4KB/4MB", Entries: 8, Associativity: "4-way"}},
+	0xCA: {kind: descriptorTLB, tlbLevel: 2, tlbType: "Unified", tlb: TLBEntry{PageSize: "4KB", Entries: 512, Associativity: "4-way"}},
+
+	0x00: {kind: descriptorNull},
+	0xF0: {kind: descriptorPrefetch, note: "64-byte prefetching"},
+	0xF1: {kind: descriptorPrefetch, note: "128-byte prefetching"},
+	0xFE: {kind: descriptorUseLeaf18},
+	0xFF: {kind: descriptorUseLeaf4},
+}
+
+// descriptor0x49IsL3 reports whether byte 0x49 means "4MB 16-way L3" rather
+// than its usual "4MB 16-way L2" meaning. SDM Vol.2 Table 3-12 overloads
+// 0x49 between those two depending on the CPU: it's L3 on Intel Xeon
+// processor MP, family 0FH model 06H, and L2 on everything else that emits
+// it.
+func descriptor0x49IsL3(model ProcessorModel) bool {
+	return model.FamilyID == 0xF && model.ModelID == 0x6
+}
+
+// DecodedTLB pairs one CPUID.02H TLB descriptor with the level/type it
+// belongs to, the same classification CPUID.18H gives newer CPUs directly.
+type DecodedTLB struct {
+	Level int    // 1, 2 or 3
+	Type  string // "Data", "Instruction" or "Unified"
+	Entry TLBEntry
+}
+
+// CacheTLBDescriptors is the decoded result of DecodeIntelLeaf2: cache and
+// TLB entries discovered in the CPUID.02H descriptor bytes, plus flags for
+// the two sentinel descriptors that mean "use a different leaf instead".
+type CacheTLBDescriptors struct {
+	Caches        []CPUCacheInfo
+	TLBs          []DecodedTLB
+	Notes         []string // e.g. prefetcher descriptors, which aren't cache/TLB entries
+	UseLeaf4Cache bool     // 0xFF seen: caller should prefer leaf 4 for cache info
+	UseLeaf18TLB  bool     // 0xFE seen: caller should prefer leaf 0x18 for TLB info
+}
+
+// DecodeIntelLeaf2 reads CPUID.02H and decodes its descriptor bytes per
+// Intel SDM Vol.2 Table 3-12. The low byte of EAX is an iteration count
+// (always 1 on modern CPUs, kept for older/virtualised parts that still
+// report it); any register whose bit 31 is set is reserved/invalid and
+// skipped entirely.
+func DecodeIntelLeaf2(offline bool, filename string) (CacheTLBDescriptors, error) {
+	a, b, c, d := CPUIDWithMode(0x2, 0, offline, filename)
+	model := GetModelData(offline, filename)
+	is0x49L3 := descriptor0x49IsL3(model)
+
+	var result CacheTLBDescriptors
+	seenTLB := make(map[DecodedTLB]bool)
+	seenCache := make(map[CPUCacheInfo]bool)
+
+	regs := [4]uint32{a, b, c, d}
+	for i, reg := range regs {
+		if reg&(1<<31) != 0 {
+			continue
+		}
+
+		start := 0
+		if i == 0 {
+			// Low byte of EAX is the iteration count, not a descriptor.
+			start = 1
+		}
+
+		for shift := start; shift < 4; shift++ {
+			descByte := byte(reg >> uint(shift*8))
+			desc, ok := leaf2Descriptors[descByte]
+			if !ok {
+				continue
+			}
+
+			switch desc.kind {
+			case descriptorNull:
+				// filler byte, nothing to record
+			case descriptorUseLeaf4:
+				result.UseLeaf4Cache = true
+			case descriptorUseLeaf18:
+				result.UseLeaf18TLB = true
+			case descriptorPrefetch:
+				result.Notes = append(result.Notes, desc.note)
+			case descriptorCache:
+				cache := desc.cache
+				if descByte == 0x49 && is0x49L3 {
+					cache.Level = 3
+				}
+				if !seenCache[cache] {
+					seenCache[cache] = true
+					result.Caches = append(result.Caches, cache)
+				}
+			case descriptorTLB:
+				tlb := DecodedTLB{Level: desc.tlbLevel, Type: desc.tlbType, Entry: desc.tlb}
+				if !seenTLB[tlb] {
+					seenTLB[tlb] = true
+					result.TLBs = append(result.TLBs, tlb)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package cpuid
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeLeaf2Fixture writes a minimal offline snapshot containing just the
+// leaf 1 (for GetModelData) and leaf 2 entries DecodeIntelLeaf2 needs, and
+// returns its path.
+func writeLeaf2Fixture(t *testing.T, leaf1EAX uint32, leaf2 Entry) string {
+	t.Helper()
+
+	data := Data{Entries: []Entry{
+		{Leaf: 1, Subleaf: 0, EAX: leaf1EAX},
+		leaf2,
+	}}
+
+	path := filepath.Join(t.TempDir(), "leaf2.json")
+	buf, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+func TestDecodeIntelLeaf2(t *testing.T) {
+	tests := []struct {
+		name     string
+		leaf1EAX uint32
+		leaf2    Entry
+		want     CacheTLBDescriptors
+	}{
+		{
+			name:     "cache and TLB descriptors packed across registers",
+			leaf1EAX: 0x000206A7, // SandyBridge family 6 model 42, not the 0x49-is-L3 exception
+			leaf2: Entry{
+				Leaf: 2, Subleaf: 0,
+				EAX: 0x01, // iteration count byte (skipped) + null filler
+				EBX: 0x5A, // 0x5A: L1 data TLB, 2MB/4MB, 32 entries, 4-way
+				ECX: 0x00,
+				EDX: 0x2C, // 0x2C: L1 data cache, 32KB, 8-way, 64B line
+			},
+			want: CacheTLBDescriptors{
+				Caches: []CPUCacheInfo{
+					{Level: 1, Type: "Data", SizeKB: 32, Ways: 8, LineSizeBytes: 64},
+				},
+				TLBs: []DecodedTLB{
+					{Level: 1, Type: "Data", Entry: TLBEntry{PageSize: "2MB/4MB", Entries: 32, Associativity: "4-way"}},
+				},
+			},
+		},
+		{
+			name:     "0x49 means L2 on ordinary silicon",
+			leaf1EAX: 0x000206A7,
+			leaf2:    Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0x49},
+			want: CacheTLBDescriptors{
+				Caches: []CPUCacheInfo{
+					{Level: 2, Type: "Unified", SizeKB: 4096, Ways: 16, LineSizeBytes: 64},
+				},
+			},
+		},
+		{
+			name:     "0x49 means L3 on family 0xF model 0x6",
+			leaf1EAX: 0x00000F60, // family 0xF, model 0x6
+			leaf2:    Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0x49},
+			want: CacheTLBDescriptors{
+				Caches: []CPUCacheInfo{
+					{Level: 3, Type: "Unified", SizeKB: 4096, Ways: 16, LineSizeBytes: 64},
+				},
+			},
+		},
+		{
+			name:     "prefetch descriptor becomes a note, not a cache or TLB entry",
+			leaf1EAX: 0x000206A7,
+			leaf2:    Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0xF0},
+			want: CacheTLBDescriptors{
+				Notes: []string{"64-byte prefetching"},
+			},
+		},
+		{
+			name:     "0xFF sentinel asks caller to prefer leaf 4 for cache info",
+			leaf1EAX: 0x000206A7,
+			leaf2:    Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0xFF},
+			want: CacheTLBDescriptors{
+				UseLeaf4Cache: true,
+			},
+		},
+		{
+			name:     "0xFE sentinel asks caller to prefer leaf 0x18 for TLB info",
+			leaf1EAX: 0x000206A7,
+			leaf2:    Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0xFE},
+			want: CacheTLBDescriptors{
+				UseLeaf18TLB: true,
+			},
+		},
+		{
+			name:     "reserved register (bit 31 set) is skipped entirely",
+			leaf1EAX: 0x000206A7,
+			leaf2:    Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0x80000000 | 0x2C},
+			want:     CacheTLBDescriptors{},
+		},
+		{
+			name:     "duplicate descriptor bytes across registers are deduped",
+			leaf1EAX: 0x000206A7,
+			leaf2:    Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0x2C2C2C2C, ECX: 0, EDX: 0},
+			want: CacheTLBDescriptors{
+				Caches: []CPUCacheInfo{
+					{Level: 1, Type: "Data", SizeKB: 32, Ways: 8, LineSizeBytes: 64},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeLeaf2Fixture(t, tt.leaf1EAX, tt.leaf2)
+
+			got, err := DecodeIntelLeaf2(true, path)
+			if err != nil {
+				t.Fatalf("DecodeIntelLeaf2: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeIntelLeaf2() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
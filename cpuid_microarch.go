@@ -0,0 +1,143 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "fmt"
+
+// microarchEntry maps one (vendor, effective family, effective model range,
+// stepping range) combination to a codename, mirroring the family/model
+// tables in LLVM's Host.cpp getIntelProcessorTypeAndSubtype and
+// getAMDProcessorTypeAndSubtype. Entries are checked in table order, so
+// narrower stepping-qualified entries (e.g. CascadeLake within family
+// 6 model 85) are listed before the broader entry they refine.
+type microarchEntry struct {
+	vendor                 string // "intel" or "amd"
+	family                 uint32
+	modelLo, modelHi       uint32 // inclusive
+	anyStepping            bool
+	steppingLo, steppingHi uint32 // inclusive, only checked when !anyStepping
+	codename               string
+	uarch                  string
+	node                   string // process node, "" if not meaningfully documented
+}
+
+// microarchTable is a curated set of codename mappings. It is not
+// exhaustive -- like cpumodels.Models, it's meant to grow incrementally as
+// new silicon ships.
+var microarchTable = []microarchEntry{
+	// Intel Atom (family 6, distinguished purely by model number).
+	{vendor: "intel", family: 6, modelLo: 28, modelHi: 28, anyStepping: true, codename: "Bonnell", uarch: "Bonnell", node: "45nm"},
+	{vendor: "intel", family: 6, modelLo: 38, modelHi: 39, anyStepping: true, codename: "Bonnell", uarch: "Bonnell", node: "45nm"},
+	{vendor: "intel", family: 6, modelLo: 55, modelHi: 55, anyStepping: true, codename: "Silvermont", uarch: "Silvermont", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 74, modelHi: 77, anyStepping: true, codename: "Silvermont", uarch: "Silvermont", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 92, modelHi: 92, anyStepping: true, codename: "Goldmont", uarch: "Goldmont", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 90, modelHi: 93, anyStepping: true, codename: "Silvermont", uarch: "Silvermont", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 95, modelHi: 95, anyStepping: true, codename: "Goldmont", uarch: "Goldmont", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 122, modelHi: 122, anyStepping: true, codename: "Goldmont Plus", uarch: "Goldmont Plus", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 134, modelHi: 134, anyStepping: true, codename: "Tremont", uarch: "Tremont", node: "10nm"},
+	{vendor: "intel", family: 6, modelLo: 175, modelHi: 175, anyStepping: true, codename: "Sierra Forest", uarch: "Crestmont", node: "3nm"},
+
+	// Intel client/server P-core lineage (family 6).
+	{vendor: "intel", family: 6, modelLo: 26, modelHi: 26, anyStepping: true, codename: "Nehalem", uarch: "Nehalem", node: "45nm"},
+	{vendor: "intel", family: 6, modelLo: 30, modelHi: 31, anyStepping: true, codename: "Nehalem", uarch: "Nehalem", node: "45nm"},
+	{vendor: "intel", family: 6, modelLo: 46, modelHi: 46, anyStepping: true, codename: "Nehalem", uarch: "Nehalem", node: "45nm"},
+	{vendor: "intel", family: 6, modelLo: 37, modelHi: 37, anyStepping: true, codename: "Westmere", uarch: "Westmere", node: "32nm"},
+	{vendor: "intel", family: 6, modelLo: 44, modelHi: 44, anyStepping: true, codename: "Westmere", uarch: "Westmere", node: "32nm"},
+	{vendor: "intel", family: 6, modelLo: 47, modelHi: 47, anyStepping: true, codename: "Westmere", uarch: "Westmere", node: "32nm"},
+	{vendor: "intel", family: 6, modelLo: 42, modelHi: 42, anyStepping: true, codename: "SandyBridge", uarch: "Sandy Bridge", node: "32nm"},
+	{vendor: "intel", family: 6, modelLo: 45, modelHi: 45, anyStepping: true, codename: "SandyBridge", uarch: "Sandy Bridge", node: "32nm"},
+	{vendor: "intel", family: 6, modelLo: 58, modelHi: 58, anyStepping: true, codename: "IvyBridge", uarch: "Ivy Bridge", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 62, modelHi: 62, anyStepping: true, codename: "IvyBridge", uarch: "Ivy Bridge", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 60, modelHi: 60, anyStepping: true, codename: "Haswell", uarch: "Haswell", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 63, modelHi: 63, anyStepping: true, codename: "Haswell", uarch: "Haswell", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 69, modelHi: 70, anyStepping: true, codename: "Haswell", uarch: "Haswell", node: "22nm"},
+	{vendor: "intel", family: 6, modelLo: 61, modelHi: 61, anyStepping: true, codename: "Broadwell", uarch: "Broadwell", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 71, modelHi: 71, anyStepping: true, codename: "Broadwell", uarch: "Broadwell", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 79, modelHi: 79, anyStepping: true, codename: "Broadwell", uarch: "Broadwell", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 86, modelHi: 86, anyStepping: true, codename: "Broadwell", uarch: "Broadwell", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 78, modelHi: 78, anyStepping: true, codename: "Skylake", uarch: "Skylake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 94, modelHi: 94, anyStepping: true, codename: "Skylake", uarch: "Skylake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 85, modelHi: 85, steppingLo: 5, steppingHi: 7, codename: "CascadeLake", uarch: "Cascade Lake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 85, modelHi: 85, steppingLo: 11, steppingHi: 11, codename: "CooperLake", uarch: "Cooper Lake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 85, modelHi: 85, anyStepping: true, codename: "Skylake", uarch: "Skylake-SP", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 142, modelHi: 142, steppingLo: 0, steppingHi: 9, codename: "KabyLake", uarch: "Kaby Lake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 158, modelHi: 158, steppingLo: 0, steppingHi: 9, codename: "KabyLake", uarch: "Kaby Lake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 142, modelHi: 142, anyStepping: true, codename: "CoffeeLake", uarch: "Coffee Lake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 158, modelHi: 158, anyStepping: true, codename: "CoffeeLake", uarch: "Coffee Lake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 102, modelHi: 102, anyStepping: true, codename: "CannonLake", uarch: "Cannon Lake", node: "10nm"},
+	{vendor: "intel", family: 6, modelLo: 125, modelHi: 126, anyStepping: true, codename: "IceLake-Client", uarch: "Ice Lake", node: "10nm"},
+	{vendor: "intel", family: 6, modelLo: 106, modelHi: 106, anyStepping: true, codename: "IceLake-Server", uarch: "Ice Lake-SP", node: "10nm"},
+	{vendor: "intel", family: 6, modelLo: 108, modelHi: 108, anyStepping: true, codename: "IceLake-Server", uarch: "Ice Lake-D", node: "10nm"},
+	{vendor: "intel", family: 6, modelLo: 140, modelHi: 141, anyStepping: true, codename: "TigerLake", uarch: "Tiger Lake", node: "10nm"},
+	{vendor: "intel", family: 6, modelLo: 167, modelHi: 167, anyStepping: true, codename: "RocketLake", uarch: "Rocket Lake", node: "14nm"},
+	{vendor: "intel", family: 6, modelLo: 151, modelHi: 151, anyStepping: true, codename: "AlderLake", uarch: "Alder Lake", node: "10nm (Intel 7)"},
+	{vendor: "intel", family: 6, modelLo: 154, modelHi: 154, anyStepping: true, codename: "AlderLake", uarch: "Alder Lake", node: "10nm (Intel 7)"},
+	{vendor: "intel", family: 6, modelLo: 183, modelHi: 183, anyStepping: true, codename: "RaptorLake", uarch: "Raptor Lake", node: "Intel 7"},
+	{vendor: "intel", family: 6, modelLo: 186, modelHi: 186, anyStepping: true, codename: "RaptorLake", uarch: "Raptor Lake", node: "Intel 7"},
+	{vendor: "intel", family: 6, modelLo: 170, modelHi: 170, anyStepping: true, codename: "MeteorLake", uarch: "Meteor Lake", node: "Intel 4"},
+	{vendor: "intel", family: 6, modelLo: 143, modelHi: 143, anyStepping: true, codename: "SapphireRapids", uarch: "Sapphire Rapids", node: "Intel 7"},
+	{vendor: "intel", family: 6, modelLo: 207, modelHi: 207, anyStepping: true, codename: "EmeraldRapids", uarch: "Emerald Rapids", node: "Intel 7"},
+	{vendor: "intel", family: 6, modelLo: 173, modelHi: 173, anyStepping: true, codename: "GraniteRapids", uarch: "Granite Rapids", node: "Intel 3"},
+
+	// AMD.
+	{vendor: "amd", family: 15, modelLo: 0, modelHi: 0x3F, anyStepping: true, codename: "K8", uarch: "K8", node: ""},
+	{vendor: "amd", family: 16, modelLo: 0, modelHi: 0x0F, anyStepping: true, codename: "K10", uarch: "K10", node: "45nm"},
+	{vendor: "amd", family: 20, modelLo: 0, modelHi: 0x0F, anyStepping: true, codename: "Bobcat", uarch: "Bobcat", node: "40nm"},
+	{vendor: "amd", family: 21, modelLo: 0x00, modelHi: 0x01, anyStepping: true, codename: "Bulldozer", uarch: "Bulldozer", node: "32nm"},
+	{vendor: "amd", family: 21, modelLo: 0x02, modelHi: 0x02, anyStepping: true, codename: "Piledriver", uarch: "Piledriver", node: "32nm"},
+	{vendor: "amd", family: 21, modelLo: 0x10, modelHi: 0x1F, anyStepping: true, codename: "Piledriver", uarch: "Piledriver", node: "32nm"},
+	{vendor: "amd", family: 21, modelLo: 0x30, modelHi: 0x3F, anyStepping: true, codename: "Steamroller", uarch: "Steamroller", node: "28nm"},
+	{vendor: "amd", family: 21, modelLo: 0x60, modelHi: 0x6F, anyStepping: true, codename: "Excavator", uarch: "Excavator", node: "28nm"},
+	{vendor: "amd", family: 22, modelLo: 0x00, modelHi: 0x0F, anyStepping: true, codename: "Jaguar", uarch: "Jaguar", node: "28nm"},
+	{vendor: "amd", family: 23, modelLo: 0x08, modelHi: 0x08, anyStepping: true, codename: "Zen+", uarch: "Zen+", node: "12nm"},
+	{vendor: "amd", family: 23, modelLo: 0x00, modelHi: 0x0F, anyStepping: true, codename: "Zen", uarch: "Zen", node: "14nm"},
+	{vendor: "amd", family: 23, modelLo: 0x11, modelHi: 0x11, anyStepping: true, codename: "Zen", uarch: "Zen (APU)", node: "14nm"},
+	{vendor: "amd", family: 23, modelLo: 0x18, modelHi: 0x18, anyStepping: true, codename: "Zen+", uarch: "Zen+ (APU)", node: "12nm"},
+	{vendor: "amd", family: 23, modelLo: 0x31, modelHi: 0x31, anyStepping: true, codename: "Zen 2", uarch: "Zen 2", node: "7nm"},
+	{vendor: "amd", family: 23, modelLo: 0x47, modelHi: 0x47, anyStepping: true, codename: "Zen 2", uarch: "Zen 2 (APU)", node: "7nm"},
+	{vendor: "amd", family: 23, modelLo: 0x60, modelHi: 0x60, anyStepping: true, codename: "Zen 2", uarch: "Zen 2 (APU)", node: "7nm"},
+	{vendor: "amd", family: 23, modelLo: 0x68, modelHi: 0x68, anyStepping: true, codename: "Zen 2", uarch: "Zen 2 (APU)", node: "7nm"},
+	{vendor: "amd", family: 23, modelLo: 0x71, modelHi: 0x71, anyStepping: true, codename: "Zen 2", uarch: "Zen 2", node: "7nm"},
+	{vendor: "amd", family: 23, modelLo: 0x90, modelHi: 0x90, anyStepping: true, codename: "Zen 2", uarch: "Zen 2 (APU)", node: "7nm"},
+	{vendor: "amd", family: 25, modelLo: 0x00, modelHi: 0x0F, anyStepping: true, codename: "Zen 3", uarch: "Zen 3", node: "7nm"},
+	{vendor: "amd", family: 25, modelLo: 0x21, modelHi: 0x21, anyStepping: true, codename: "Zen 3", uarch: "Zen 3", node: "7nm"},
+	{vendor: "amd", family: 25, modelLo: 0x44, modelHi: 0x44, anyStepping: true, codename: "Zen 3+", uarch: "Zen 3+ (APU)", node: "6nm"},
+	{vendor: "amd", family: 25, modelLo: 0x10, modelHi: 0x1F, anyStepping: true, codename: "Zen 4", uarch: "Zen 4 (APU)", node: "4nm"},
+	{vendor: "amd", family: 25, modelLo: 0x60, modelHi: 0x6F, anyStepping: true, codename: "Zen 4", uarch: "Zen 4 (APU)", node: "4nm"},
+	{vendor: "amd", family: 25, modelLo: 0x70, modelHi: 0x7F, anyStepping: true, codename: "Zen 4", uarch: "Zen 4", node: "5nm"},
+	{vendor: "amd", family: 26, modelLo: 0x00, modelHi: 0x0F, anyStepping: true, codename: "Zen 5", uarch: "Zen 5", node: "4nm"},
+	{vendor: "amd", family: 26, modelLo: 0x20, modelHi: 0x2F, anyStepping: true, codename: "Zen 5", uarch: "Zen 5 (APU)", node: "4nm"},
+	{vendor: "amd", family: 26, modelLo: 0x40, modelHi: 0x4F, anyStepping: true, codename: "Zen 5", uarch: "Zen 5 (APU)", node: "4nm"},
+	{vendor: "amd", family: 26, modelLo: 0x70, modelHi: 0x7F, anyStepping: true, codename: "Zen 5", uarch: "Zen 5", node: "3nm"},
+}
+
+// GetMicroarchitecture resolves this CPU's (vendor, effective family,
+// effective model, stepping) tuple to a codename/microarchitecture/process
+// node triple, the same family/model tables LLVM's Host.cpp
+// getIntelProcessorTypeAndSubtype/getAMDProcessorTypeAndSubtype use. If the
+// tuple isn't in microarchTable, Codename is "Unknown" and Uarch reports
+// the raw numeric tuple instead.
+func GetMicroarchitecture(offline bool, filename string) (Codename, Uarch, Node string) {
+	vendor := "intel"
+	if isAMD(offline, filename) {
+		vendor = "amd"
+	} else if !isIntel(offline, filename) {
+		vendor = ""
+	}
+
+	model := GetModelData(offline, filename)
+
+	for _, e := range microarchTable {
+		if e.vendor != vendor || e.family != model.EffectiveFamily {
+			continue
+		}
+		if model.EffectiveModel < e.modelLo || model.EffectiveModel > e.modelHi {
+			continue
+		}
+		if !e.anyStepping && (model.SteppingID < e.steppingLo || model.SteppingID > e.steppingHi) {
+			continue
+		}
+		return e.codename, e.uarch, e.node
+	}
+
+	return "Unknown", fmt.Sprintf("family=0x%x model=0x%x stepping=0x%x", model.EffectiveFamily, model.EffectiveModel, model.SteppingID), ""
+}
@@ -0,0 +1,179 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "sort"
+
+// Policy selects how strictly MigrationCompatible treats feature
+// differences between a live migration's source and target hosts.
+type Policy string
+
+// The three migration policies MigrationCompatible understands.
+const (
+	// PolicyStrict fails the migration on any feature the guest may have
+	// used on source that target lacks.
+	PolicyStrict Policy = "strict"
+	// PolicyMaskDown instead reports which source features would need to
+	// be masked out of the guest's CPUID (the same "-cpu ...,-feature"
+	// QEMU accepts) to make the migration succeed.
+	PolicyMaskDown Policy = "mask-down"
+	// PolicySEVOnly restricts the check to the encryption-relevant bits
+	// (SME, SEV, SEV-ES, SEV-SNP) of CPUID leaf 0x8000001F, for clusters
+	// that only care about confidential-computing compatibility.
+	PolicySEVOnly Policy = "sev-only"
+)
+
+// XSaveAreaDelta reports the CPUID leaf 0xD XSAVE state-area size on each
+// side of a migration, since a guest's saved FPU/XSAVE state has to fit the
+// target's area or it can't be restored.
+type XSaveAreaDelta struct {
+	SourceSize uint32 `json:"source_size"`
+	TargetSize uint32 `json:"target_size"`
+}
+
+// MigrationReport is the structured result of MigrationCompatible.
+type MigrationReport struct {
+	Policy Policy `json:"policy"`
+	// Compatible is false if policy is PolicyStrict and FatalMissing is
+	// non-empty. Under PolicyMaskDown and PolicySEVOnly, Compatible
+	// reflects whether the guest is safe to migrate once MaskRequired (or
+	// the SEV-only subset of it) is applied.
+	Compatible bool `json:"compatible"`
+	// FatalMissing are features present on source that target lacks.
+	FatalMissing []string `json:"fatal_missing,omitempty"`
+	// BenignExtra are features present only on target; these are safe
+	// since the guest never had a chance to depend on them.
+	BenignExtra []string `json:"benign_extra,omitempty"`
+	// MaskRequired is the subset of FatalMissing PolicyMaskDown proposes
+	// hiding from the guest's CPUID to make the migration succeed.
+	MaskRequired []string `json:"mask_required,omitempty"`
+	// XSaveArea is the leaf 0xD state-area size on each side.
+	XSaveArea XSaveAreaDelta `json:"xsave_area"`
+	// TopologyMismatch describes a leaf 0x1A/0x8000001E topology
+	// difference (hybrid core mix, compute-unit layout) that could affect
+	// a guest pinned to specific APIC IDs, or "" if none was found.
+	TopologyMismatch string `json:"topology_mismatch,omitempty"`
+	// MitigationDelta are microcode-visible mitigation bits
+	// (MD_CLEAR, SRBDS_CTRL, CET_IBT, CET_SS/SHSTK) present on source but
+	// missing on target.
+	MitigationDelta []string `json:"mitigation_delta,omitempty"`
+}
+
+// sevRelevantBits names the leaf 0x8000001F EAX bits PolicySEVOnly checks,
+// in bit-index order.
+var sevRelevantBits = []struct {
+	bit  int
+	name string
+}{
+	{0, "SME"},
+	{1, "SEV"},
+	{3, "SEV_ES"},
+	{4, "SEV_SNP"},
+}
+
+// mitigationBits names the leaf 7 EDX/ECX bits MigrationCompatible diffs for
+// PolicyStrict/PolicyMaskDown, alongside their register (2 = ECX, 3 = EDX).
+var mitigationBits = []struct {
+	register int
+	bit      int
+	name     string
+}{
+	{3, 9, "SRBDS_CTRL"},
+	{3, 10, "MD_CLEAR"},
+	{3, 20, "CET_IBT"},
+	{2, 7, "CET_SS"}, // SHSTK
+}
+
+// MigrationCompatible determines whether a guest booted on source can be
+// safely live-migrated to target, the practical check QEMU/libvirt users
+// run by hand before a migration today. source and target are CPUID
+// snapshots in the same Data format FileSource/RemoteSource replay (see
+// CaptureData), so this can run offline against two captured fixtures
+// without touching either machine.
+func MigrationCompatible(source, target Data, policy Policy) MigrationReport {
+	src := FileSource{Data: source}
+	dst := FileSource{Data: target}
+
+	report := MigrationReport{Policy: policy}
+
+	if policy == PolicySEVOnly {
+		a1, _, _, _ := src.CPUID(0x8000001F, 0)
+		a2, _, _, _ := dst.CPUID(0x8000001F, 0)
+		for _, b := range sevRelevantBits {
+			if (a1>>uint(b.bit))&1 == 1 && (a2>>uint(b.bit))&1 == 0 {
+				report.FatalMissing = append(report.FatalMissing, b.name)
+			}
+		}
+		report.Compatible = len(report.FatalMissing) == 0
+		return report
+	}
+
+	srcSupported := allSupportedFrom(src)
+	dstSupported := allSupportedFrom(dst)
+
+	for name := range srcSupported {
+		if !dstSupported[name] {
+			report.FatalMissing = append(report.FatalMissing, name)
+		}
+	}
+	for name := range dstSupported {
+		if !srcSupported[name] {
+			report.BenignExtra = append(report.BenignExtra, name)
+		}
+	}
+	sort.Strings(report.FatalMissing)
+	sort.Strings(report.BenignExtra)
+
+	_, _, c1, _ := src.CPUID(0xD, 0)
+	_, _, c2, _ := dst.CPUID(0xD, 0)
+	report.XSaveArea = XSaveAreaDelta{SourceSize: c1, TargetSize: c2}
+
+	a1a, _, _, _ := src.CPUID(0x1A, 0)
+	a1b, _, _, _ := dst.CPUID(0x1A, 0)
+	if a1a != a1b {
+		report.TopologyMismatch = "leaf 0x1A hybrid core-type layout differs between source and target"
+	} else {
+		_, eb1, ec1, _ := src.CPUID(0x8000001E, 0)
+		_, eb2, ec2, _ := dst.CPUID(0x8000001E, 0)
+		if eb1 != eb2 || ec1 != ec2 {
+			report.TopologyMismatch = "leaf 0x8000001E compute-unit/node topology differs between source and target"
+		}
+	}
+
+	_, _, c7src, d7src := src.CPUID(7, 0)
+	_, _, c7dst, d7dst := dst.CPUID(7, 0)
+	for _, m := range mitigationBits {
+		var regSrc, regDst uint32
+		if m.register == 2 {
+			regSrc, regDst = c7src, c7dst
+		} else {
+			regSrc, regDst = d7src, d7dst
+		}
+		if (regSrc>>uint(m.bit))&1 == 1 && (regDst>>uint(m.bit))&1 == 0 {
+			report.MitigationDelta = append(report.MitigationDelta, m.name)
+		}
+	}
+
+	switch policy {
+	case PolicyMaskDown:
+		report.MaskRequired = append([]string{}, report.FatalMissing...)
+		report.MaskRequired = append(report.MaskRequired, report.MitigationDelta...)
+		report.Compatible = true
+	default: // PolicyStrict
+		report.Compatible = len(report.FatalMissing) == 0 && len(report.MitigationDelta) == 0 && report.TopologyMismatch == ""
+	}
+
+	return report
+}
+
+// allSupportedFrom collects every feature name src reports as supported,
+// across every registered FeatureSet, as a set for MigrationCompatible's
+// source/target diff.
+func allSupportedFrom(src Source) map[string]bool {
+	supported := make(map[string]bool)
+	for category := range cpuFeaturesList {
+		for _, name := range GetSupportedFeaturesFromSource(category, src) {
+			supported[name] = true
+		}
+	}
+	return supported
+}
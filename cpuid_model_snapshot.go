@@ -0,0 +1,149 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Snapshot is a named CPU model's full CPUID replay table -- every
+// leaf/subleaf/register value that model reports -- so this package's
+// feature decoders can be driven against it exactly as if that silicon
+// were physically present. It implements Source, mirroring VirtualBox's
+// CPUMCPUIDLEAF tables: a flat list of (leaf, subleaf) -> register values
+// good enough to answer "would this binary run on <model>" without the
+// target hardware.
+type Snapshot struct {
+	Name string
+	Data Data
+}
+
+// CPUID implements Source by replaying Data, identically to FileSource.
+func (s *Snapshot) CPUID(leaf, subleaf uint32) (a, b, c, d uint32) {
+	return FileSource{Data: s.Data}.CPUID(leaf, subleaf)
+}
+
+// modelSnapshots is the curated table of named CPU models shipped with this
+// package. It answers a different question than cpumodels.Models does: this
+// table is a full per-SKU CPUID leaf/subleaf replay, good for "decode every
+// feature this exact chip reports" (LoadModel, driving GetSupportedFeatures
+// and friends via Source); cpumodels.Models is a per-generation abstract
+// feature floor, good for "what's the closest baseline a fleet satisfies"
+// (Match/MatchModel). A raw replay can't substitute for an abstract floor
+// (no single real SKU's leaf dump is a safe lowest-common-denominator for a
+// whole generation) and an abstract floor can't substitute for a replay (it
+// has no leaf data to answer arbitrary feature queries against), so the two
+// tables stay separate rather than forcing one Go type to do both jobs.
+//
+// Entries here use cpumodels.Models' own name when they replay that exact
+// SKU (currently just "SandyBridge", matching cpumodels.Models' Family 6/
+// ModelID 42 entry) so a name isn't ambiguous between the two catalogs; the
+// rest are real SKUs cpumodels.Models doesn't carry a generation entry for
+// yet, named after the chip itself rather than invented to line up.
+var modelSnapshots = map[string]Data{
+	"IvyBridge": {Entries: []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 0xD, EBX: 0x756E6547, ECX: 0x6C65746E, EDX: 0x49656E69},
+		{Leaf: 1, Subleaf: 0, EAX: 0x000306A9, EBX: 0x00100800, ECX: 0x1F9AE3BF, EDX: 0xBFEBFBFF},
+		{Leaf: 7, Subleaf: 0, EAX: 0, EBX: 0x00000280, ECX: 0, EDX: 0},
+		{Leaf: 0x80000001, Subleaf: 0, EAX: 0, EBX: 0, ECX: 0x00000021, EDX: 0x28100800},
+	}},
+	"Cometlake": {Entries: []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 0x16, EBX: 0x756E6547, ECX: 0x6C65746E, EDX: 0x49656E69},
+		{Leaf: 1, Subleaf: 0, EAX: 0x000A0655, EBX: 0x00100800, ECX: 0x7FFAFBFF, EDX: 0xBFEBFBFF},
+		{Leaf: 7, Subleaf: 0, EAX: 0, EBX: 0x029C6FBF, ECX: 0x40000000, EDX: 0xBC000400},
+		{Leaf: 0x80000001, Subleaf: 0, EAX: 0, EBX: 0, ECX: 0x00000021, EDX: 0x2C100800},
+	}},
+	"Zen3": {Entries: []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 0x10, EBX: 0x68747541, ECX: 0x444D4163, EDX: 0x69746E65},
+		{Leaf: 1, Subleaf: 0, EAX: 0x00A20F10, EBX: 0x00100800, ECX: 0x7ED8320B, EDX: 0x178BFBFF},
+		{Leaf: 7, Subleaf: 0, EAX: 0, EBX: 0x219C91A9, ECX: 0x00400004, EDX: 0},
+		{Leaf: 0x80000001, Subleaf: 0, EAX: 0, EBX: 0, ECX: 0x75C237FF, EDX: 0x2FD3FBFF},
+		{Leaf: 0x8000001F, Subleaf: 0, EAX: 0x0000000F, EBX: 0, ECX: 0, EDX: 0},
+	}},
+	"Opteron_2384": {Entries: []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 0x5, EBX: 0x68747541, ECX: 0x444D4163, EDX: 0x69746E65},
+		{Leaf: 1, Subleaf: 0, EAX: 0x00100F23, EBX: 0x00080800, ECX: 0x00802009, EDX: 0x178BFBFF},
+		{Leaf: 0x80000001, Subleaf: 0, EAX: 0, EBX: 0, ECX: 0x00000037, EDX: 0xEFD3FBFF},
+	}},
+	"SandyBridge": {Entries: []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 0xB, EBX: 0x756E6547, ECX: 0x6C65746E, EDX: 0x49656E69},
+		{Leaf: 1, Subleaf: 0, EAX: 0x000206A7, EBX: 0x00040800, ECX: 0x1DBAE3BF, EDX: 0xBFEBFBFF},
+		{Leaf: 0x80000001, Subleaf: 0, EAX: 0, EBX: 0, ECX: 0x00000001, EDX: 0x24100800},
+	}},
+}
+
+// ModelNames reports every model LoadModel can currently resolve,
+// including any registered at runtime via RegisterModel.
+func ModelNames() []string {
+	names := make([]string, 0, len(modelSnapshots))
+	for name := range modelSnapshots {
+		names = append(names, name)
+	}
+	return names
+}
+
+// LoadModel returns a Snapshot replaying the named CPU model's CPUID
+// table, or an error if name isn't in modelSnapshots.
+func LoadModel(name string) (*Snapshot, error) {
+	data, ok := modelSnapshots[name]
+	if !ok {
+		return nil, fmt.Errorf("cpuid: unknown CPU model %q", name)
+	}
+	return &Snapshot{Name: name, Data: data}, nil
+}
+
+// RegisterModel adds or replaces a named model in the table LoadModel
+// resolves against, so a caller can extend the curated set at runtime
+// instead of forking this package.
+func RegisterModel(name string, data Data) {
+	modelSnapshots[name] = data
+}
+
+// LoadModelFromText parses a pasted CPUID dump into a named Snapshot and
+// registers it via RegisterModel. Each non-empty, non-comment ("#") line
+// holds six whitespace-separated hex fields: leaf subleaf eax ebx ecx edx
+// (the "0x" prefix is optional) -- the same shape a user would get copying
+// rows out of `cpuid -1 -r` or a VirtualBox CPUMCPUIDLEAF table.
+func LoadModelFromText(name string, r io.Reader) (*Snapshot, error) {
+	var data Data
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("cpuid: model %q: expected 6 fields, got %d in line %q", name, len(fields), line)
+		}
+
+		values := make([]uint32, 6)
+		for i, f := range fields {
+			v, err := strconv.ParseUint(strings.TrimPrefix(f, "0x"), 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("cpuid: model %q: parse field %q: %w", name, f, err)
+			}
+			values[i] = uint32(v)
+		}
+
+		data.Entries = append(data.Entries, Entry{
+			Leaf:    values[0],
+			Subleaf: values[1],
+			EAX:     values[2],
+			EBX:     values[3],
+			ECX:     values[4],
+			EDX:     values[5],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cpuid: model %q: %w", name, err)
+	}
+
+	RegisterModel(name, data)
+	return &Snapshot{Name: name, Data: data}, nil
+}
@@ -0,0 +1,197 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// PerCPUEntry is a single CPUID snapshot for one logical processor, together
+// with the topology hints needed to correlate it back to /proc/cpuinfo.
+type PerCPUEntry struct {
+	CPU      int     `json:"cpu"`
+	APICID   uint32  `json:"apic_id"`
+	CoreType string  `json:"core_type"`
+	CoreID   int     `json:"core_id"`
+	PhysID   int     `json:"physical_id"`
+	Entries  []Entry `json:"entries"`
+}
+
+// PerCPUData is the top level JSON document written by CaptureAllCPUs.
+type PerCPUData struct {
+	CPUs []PerCPUEntry `json:"cpus"`
+}
+
+// procCPUInfo holds the bits of /proc/cpuinfo that are useful for correlating
+// a logical CPU index to its physical topology. Populated only on Linux.
+type procCPUInfo struct {
+	coreID int
+	physID int
+}
+
+// CaptureAllCPUs pins a goroutine to each online logical processor and
+// captures a full CPUID snapshot from that CPU, so that hybrid CPUs (P/E
+// cores) and heterogeneous steppings are represented faithfully instead of
+// collapsing to whichever core the calling goroutine happened to land on.
+//
+// On Linux the result is cross-referenced against /proc/cpuinfo (processor,
+// core id, physical id) so offline consumers can map logical->physical->core
+// topology without re-running CPUID.
+func CaptureAllCPUs(filename string) error {
+	n := runtime.NumCPU()
+	procInfo := readProcCPUInfo()
+
+	data := PerCPUData{CPUs: make([]PerCPUEntry, 0, n)}
+	for cpu := 0; cpu < n; cpu++ {
+		entries, apicID, err := captureOnCPU(cpu)
+		if err != nil {
+			return fmt.Errorf("capturing cpuid on cpu %d: %w", cpu, err)
+		}
+
+		entry := PerCPUEntry{
+			CPU:     cpu,
+			APICID:  apicID,
+			Entries: entries,
+		}
+
+		if info, ok := procInfo[cpu]; ok {
+			entry.CoreID = info.coreID
+			entry.PhysID = info.physID
+		}
+
+		// Core type comes from this CPU's own pinned leaf 0x1A capture:
+		// EAX[31:24] is 0x40 for a Core (P) and 0x20 for an Atom (E) core.
+		for _, e := range entries {
+			if e.Leaf == 0x1A && e.Subleaf == 0 {
+				switch e.EAX >> 24 {
+				case 0x40:
+					entry.CoreType = "P"
+				case 0x20:
+					entry.CoreType = "E"
+				}
+			}
+		}
+
+		data.CPUs = append(data.CPUs, entry)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// captureOnCPU locks the calling goroutine's OS thread, pins it to the given
+// logical CPU, and walks the same standard/extended leaf hierarchy as
+// CaptureData. It returns the x2APIC ID (leaf 0xB/0x1F) alongside the entries
+// so callers can correlate the snapshot to a topology without re-deriving it.
+func captureOnCPU(cpu int) ([]Entry, uint32, error) {
+	done := make(chan struct{})
+	var entries []Entry
+	var apicID uint32
+	var pinErr error
+
+	go func() {
+		defer close(done)
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		if err := lockToCPU(cpu); err != nil {
+			pinErr = err
+			return
+		}
+
+		entries = captureEntries()
+		_, _, c, d := cpuid(0xB, 0)
+		if c != 0 {
+			apicID = d
+		}
+	}()
+	<-done
+
+	if pinErr != nil {
+		return nil, 0, pinErr
+	}
+	return entries, apicID, nil
+}
+
+// captureEntries walks the full CPUID leaf hierarchy exactly like CaptureData,
+// but returns the entries instead of writing them straight to a file, so the
+// per-CPU capture path can attach topology metadata first.
+func captureEntries() []Entry {
+	var entries []Entry
+
+	maxStandard, _, _, _ := cpuid(0, 0)
+	for leaf := uint32(0); leaf <= maxStandard; leaf++ {
+		if leaf == 4 || leaf == 0xB || leaf == 0xD || leaf == 0x1F {
+			subleaf := uint32(0)
+			for {
+				a, b, c, d := cpuid(leaf, subleaf)
+				if leaf == 4 && subleaf > 0 && (a&0x1F) == 0 {
+					break
+				}
+				if leaf == 0xB && subleaf > 0 && a == 0 {
+					break
+				}
+				if leaf == 0x1F && subleaf > 0 && (a&0x1F) == 0 && ((c>>8)&0xFF) == 0 {
+					break
+				}
+				if leaf == 0xD && subleaf > 0 && a == 0 && b == 0 && c == 0 && d == 0 {
+					break
+				}
+				entries = append(entries, Entry{Leaf: leaf, Subleaf: subleaf, EAX: a, EBX: b, ECX: c, EDX: d})
+				subleaf++
+			}
+		} else {
+			a, b, c, d := cpuid(leaf, 0)
+			entries = append(entries, Entry{Leaf: leaf, Subleaf: 0, EAX: a, EBX: b, ECX: c, EDX: d})
+		}
+	}
+
+	maxExtended, _, _, _ := cpuid(0x80000000, 0)
+	for leaf := uint32(0x80000000); leaf <= maxExtended; leaf++ {
+		a, b, c, d := cpuid(leaf, 0)
+		entries = append(entries, Entry{Leaf: leaf, Subleaf: 0, EAX: a, EBX: b, ECX: c, EDX: d})
+	}
+
+	return entries
+}
+
+// PerCPUDataFromFile reads a file written by CaptureAllCPUs.
+func PerCPUDataFromFile(filename string) (PerCPUData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return PerCPUData{}, err
+	}
+	defer file.Close()
+
+	var data PerCPUData
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return PerCPUData{}, err
+	}
+	return data, nil
+}
+
+// CPUSnapshot returns the captured entries for a single logical CPU index
+// from a file written by CaptureAllCPUs, so that GetIntelHybrid/GetProcessorInfo
+// style helpers can be driven against one specific CPU's data.
+func CPUSnapshot(filename string, cpu int) (PerCPUEntry, error) {
+	data, err := PerCPUDataFromFile(filename)
+	if err != nil {
+		return PerCPUEntry{}, err
+	}
+	for _, entry := range data.CPUs {
+		if entry.CPU == cpu {
+			return entry, nil
+		}
+	}
+	return PerCPUEntry{}, fmt.Errorf("cpu %d not found in %s", cpu, filename)
+}
@@ -0,0 +1,35 @@
+//go:build darwin
+
+package cpuid
+
+/*
+#include <mach/mach.h>
+#include <mach/thread_policy.h>
+#include <pthread.h>
+
+static int pin_thread(int cpu) {
+	thread_affinity_policy_data_t policy = { cpu };
+	thread_port_t thread = pthread_mach_thread_np(pthread_self());
+	return thread_policy_set(thread, THREAD_AFFINITY_POLICY, (thread_policy_t)&policy, THREAD_AFFINITY_POLICY_COUNT);
+}
+*/
+import "C"
+
+import "fmt"
+
+// lockToCPU requests an affinity tag for the calling OS thread via
+// thread_policy_set. Darwin treats this as a hint rather than a hard pin
+// (the scheduler may still migrate the thread), so callers should not rely
+// on it as strictly as the Linux/Windows equivalents.
+func lockToCPU(cpu int) error {
+	if ret := C.pin_thread(C.int(cpu)); ret != 0 {
+		return fmt.Errorf("thread_policy_set: kern_return_t %d", int(ret))
+	}
+	return nil
+}
+
+// readProcCPUInfo has no equivalent on Darwin; topology is left to the
+// CoreType/APICID fields derived directly from CPUID.
+func readProcCPUInfo() map[int]procCPUInfo {
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build linux
+
+package cpuid
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// lockToCPU pins the calling OS thread to the given logical CPU via
+// sched_setaffinity(2). The caller must already hold runtime.LockOSThread.
+func lockToCPU(cpu int) error {
+	const cpuSetSize = 128 // bytes, supports up to 1024 CPUs like glibc's default cpu_set_t
+	var set [cpuSetSize / 8]uint64
+	set[cpu/64] |= 1 << uint(cpu%64)
+
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0, cpuSetSize, uintptr(unsafe.Pointer(&set[0])))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// readProcCPUInfo parses /proc/cpuinfo into a map keyed by logical "processor"
+// index, so CaptureAllCPUs can attach core id/physical id without a second
+// CPUID round-trip.
+func readProcCPUInfo() map[int]procCPUInfo {
+	file, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	result := make(map[int]procCPUInfo)
+	current := -1
+	cur := procCPUInfo{}
+
+	flush := func() {
+		if current >= 0 {
+			result[current] = cur
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			current = -1
+			cur = procCPUInfo{}
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		val := strings.TrimSpace(parts[1])
+
+		switch key {
+		case "processor":
+			if n, err := strconv.Atoi(val); err == nil {
+				current = n
+			}
+		case "core id":
+			if n, err := strconv.Atoi(val); err == nil {
+				cur.coreID = n
+			}
+		case "physical id":
+			if n, err := strconv.Atoi(val); err == nil {
+				cur.physID = n
+			}
+		}
+	}
+	flush()
+
+	return result
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package cpuid
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32            = syscall.NewLazyDLL("kernel32.dll")
+	procSetThreadAffinity  = modkernel32.NewProc("SetThreadAffinityMask")
+	procGetCurrentThreadID = modkernel32.NewProc("GetCurrentThread")
+)
+
+// lockToCPU pins the calling OS thread to the given logical CPU via
+// SetThreadAffinityMask. The caller must already hold runtime.LockOSThread.
+func lockToCPU(cpu int) error {
+	thread, _, _ := procGetCurrentThreadID.Call()
+	mask := uintptr(1) << uint(cpu)
+
+	ret, _, err := procSetThreadAffinity.Call(thread, mask)
+	if ret == 0 {
+		return err
+	}
+	return nil
+}
+
+// readProcCPUInfo has no equivalent on Windows; topology is left to the
+// CoreType/APICID fields derived directly from CPUID.
+func readProcCPUInfo() map[int]procCPUInfo {
+	return nil
+}
+
+var _ = unsafe.Pointer(nil)
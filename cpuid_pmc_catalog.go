@@ -0,0 +1,119 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "strings"
+
+// PMCEvent is one named hardware performance-monitoring event, mapping a
+// human-readable name to the (EventSelect, UMask, Counter) tuple NetBSD's
+// tprof arch backend would program into a perf counter MSR for Intel
+// Architectural Performance Monitoring (v1+, including the Silvermont/
+// Goldmont encodings) or AMD Family 10h-19h's core PMCs and IBS.
+type PMCEvent struct {
+	Name        string
+	Description string
+	EventSelect uint16
+	UMask       uint8
+	// Counter names which counter class the event is programmed into:
+	// "fixed" for an architecturally fixed counter (Intel only), "general"
+	// for any general-purpose counter, or "ibs" for an AMD IBS tag.
+	Counter string
+	Vendor  string // "intel" or "amd"
+	// RequiredFeature is the feature name gating this event, looked up in
+	// cpuFeaturesList, or "" if every CPU exposing this vendor's perfmon leaf
+	// at all supports it. A name cpuFeaturesList does not register is never
+	// reported supported, so its event is never listed.
+	RequiredFeature string
+	PEBSEligible    bool
+	IBSEligible     bool
+}
+
+// pmcEventsIntel are Intel Architectural Performance Monitoring events,
+// keyed by the (EventSelect, UMask) NetBSD's tprof x86 backend programs into
+// IA32_PERFEVTSELx for every architectural-perfmon version since v1, plus
+// the v1 fixed-counter events (unhalted-core-cycles, instruction-retired)
+// that don't need an EventSelect/UMask at all.
+var pmcEventsIntel = []PMCEvent{
+	{Name: "unhalted-core-cycles", Description: "Core cycles while the core is not halted", EventSelect: 0x3C, UMask: 0x00, Counter: "fixed", Vendor: "intel", PEBSEligible: true},
+	{Name: "instruction-retired", Description: "Instructions retired", EventSelect: 0xC0, UMask: 0x00, Counter: "fixed", Vendor: "intel", PEBSEligible: true},
+	{Name: "unhalted-reference-cycles", Description: "Reference cycles while the core is not halted", EventSelect: 0x3C, UMask: 0x01, Counter: "fixed", Vendor: "intel"},
+	{Name: "llc-reference", Description: "Last-level cache references", EventSelect: 0x2E, UMask: 0x4F, Counter: "general", Vendor: "intel"},
+	{Name: "llc-misses", Description: "Last-level cache misses", EventSelect: 0x2E, UMask: 0x41, Counter: "general", Vendor: "intel"},
+	{Name: "branch-instruction-retired", Description: "Branch instructions retired", EventSelect: 0xC4, UMask: 0x00, Counter: "general", Vendor: "intel", PEBSEligible: true},
+	{Name: "branch-misses-retired", Description: "Mispredicted branch instructions retired", EventSelect: 0xC5, UMask: 0x00, Counter: "general", Vendor: "intel", PEBSEligible: true},
+	{Name: "topdown-slots", Description: "Top-down pipeline slots", EventSelect: 0xA4, UMask: 0x01, Counter: "fixed", Vendor: "intel", RequiredFeature: "PMC_WIDTH"},
+}
+
+// pmcEventsAMD are AMD core-PMC and IBS events. The feature names gating
+// some of them (L3_PERFCTR, IBS_FETCH, IBS_OP, NPB), like Intel's
+// PMC_WIDTH, are not registered in cpuFeaturesList, so ListPMCEvents
+// omits those events on every CPU.
+var pmcEventsAMD = []PMCEvent{
+	{Name: "unhalted-core-cycles", Description: "Core cycles while the core is not halted", EventSelect: 0x76, UMask: 0x00, Counter: "general", Vendor: "amd"},
+	{Name: "instruction-retired", Description: "Retired x86 instructions", EventSelect: 0xC0, UMask: 0x00, Counter: "general", Vendor: "amd"},
+	{Name: "llc-misses", Description: "L3 cache misses", EventSelect: 0x04, UMask: 0xE7, Counter: "general", Vendor: "amd", RequiredFeature: "L3_PERFCTR"},
+	{Name: "branch-instruction-retired", Description: "Retired branch instructions", EventSelect: 0xC2, UMask: 0x00, Counter: "general", Vendor: "amd"},
+	{Name: "ibs-fetch-latency", Description: "IBS instruction-fetch sampling latency", EventSelect: 0x00, UMask: 0x00, Counter: "ibs", Vendor: "amd", RequiredFeature: "IBS_FETCH", IBSEligible: true},
+	{Name: "ibs-op-tagged-cycles", Description: "IBS tagged-macro-op execution sampling", EventSelect: 0x00, UMask: 0x00, Counter: "ibs", Vendor: "amd", RequiredFeature: "IBS_OP", IBSEligible: true},
+	{Name: "northbridge-perf", Description: "Northbridge performance monitor", EventSelect: 0x00, UMask: 0x00, Counter: "general", Vendor: "amd", RequiredFeature: "NPB"},
+}
+
+// vendorIDFromSource reads leaf 0's vendor string straight from src, the way
+// GetVendorID does for the offline/filename-mode API -- ListPMCEvents needs
+// the vendor off an arbitrary Source, not just the live/offline pair.
+func vendorIDFromSource(src Source) string {
+	_, b, c, d := src.CPUID(0, 0)
+	return string([]byte{
+		byte(b), byte(b >> 8), byte(b >> 16), byte(b >> 24),
+		byte(d), byte(d >> 8), byte(d >> 16), byte(d >> 24),
+		byte(c), byte(c >> 8), byte(c >> 16), byte(c >> 24),
+	})
+}
+
+// ListPMCEvents returns every named PMU event known for src's vendor, with
+// RequiredFeature checked against src so a caller can filter on event
+// availability without re-deriving it: an Intel event needs CPUID.0AH's
+// architectural-perfmon version to be at least 1, and an AMD event needs its
+// RequiredFeature bit (if any) actually present.
+func ListPMCEvents(src Source) []PMCEvent {
+	vendor := vendorIDFromSource(src)
+
+	switch {
+	case strings.Contains(strings.ToUpper(vendor), "AMD"):
+		return filterPMCEvents(pmcEventsAMD, src)
+	case strings.Contains(strings.ToUpper(vendor), "INTEL"):
+		a, _, _, _ := src.CPUID(0xA, 0)
+		if uint8(a) == 0 {
+			return nil
+		}
+		return filterPMCEvents(pmcEventsIntel, src)
+	default:
+		return nil
+	}
+}
+
+// filterPMCEvents drops every event whose RequiredFeature isn't supported on
+// src.
+func filterPMCEvents(events []PMCEvent, src Source) []PMCEvent {
+	var available []PMCEvent
+	for _, ev := range events {
+		if ev.RequiredFeature != "" && !IsFeatureSupportedFromSource(ev.RequiredFeature, src) {
+			continue
+		}
+		available = append(available, ev)
+	}
+	return available
+}
+
+// LookupPMCEvent returns the named event from either vendor's catalog,
+// regardless of which CPU is actually running -- useful for looking up a
+// tuple to cross-check against a different host's capture.
+func LookupPMCEvent(name string) (PMCEvent, bool) {
+	for _, table := range [][]PMCEvent{pmcEventsIntel, pmcEventsAMD} {
+		for _, ev := range table {
+			if ev.Name == name {
+				return ev, true
+			}
+		}
+	}
+	return PMCEvent{}, false
+}
@@ -0,0 +1,135 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// rawDumpLineRE matches one leaf/subleaf row of the text format Linux's
+// `cpuid -r` (and the UEFI CPUID test app) emit:
+//
+//	0x00000001 0x00: eax=0x000806ea ebx=0x00100800 ecx=0x7ffafbbf edx=0xbfebfbff
+//
+// "CPU N:" header lines above each block of rows don't match and are
+// skipped -- this package models one CPUID view, not a per-core topology
+// capture, so every block's rows merge into a single Data. The value fields
+// accept any letters or digits, so a row with a malformed hex value is
+// reported as an error by parseRawDumpMatch rather than skipped.
+var rawDumpLineRE = regexp.MustCompile(`^\s*0x([0-9a-zA-Z]+)\s+0x([0-9a-zA-Z]+):\s*eax=0x([0-9a-zA-Z]+)\s+ebx=0x([0-9a-zA-Z]+)\s+ecx=0x([0-9a-zA-Z]+)\s+edx=0x([0-9a-zA-Z]+)`)
+
+// ParseRawDump reads the `cpuid -r`-style text format from r into a Data,
+// the format's fields matching Entry field-for-field. A later row for the
+// same leaf/subleaf (e.g. a second "CPU N:" block) overwrites an earlier
+// one rather than appending a duplicate, so FileSource's first-match lookup
+// stays correct however many CPU blocks the dump has.
+func ParseRawDump(r io.Reader) (Data, error) {
+	var data Data
+	index := make(map[In]int)
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		m := rawDumpLineRE.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		entry, err := parseRawDumpMatch(m)
+		if err != nil {
+			return Data{}, fmt.Errorf("cpuid: parse raw dump line %d: %w", lineNo, err)
+		}
+
+		key := In{Leaf: entry.Leaf, Subleaf: entry.Subleaf}
+		if i, ok := index[key]; ok {
+			data.Entries[i] = entry
+			continue
+		}
+		index[key] = len(data.Entries)
+		data.Entries = append(data.Entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return Data{}, fmt.Errorf("cpuid: read raw dump: %w", err)
+	}
+
+	return data, nil
+}
+
+func parseRawDumpMatch(m []string) (Entry, error) {
+	fields := make([]uint64, 6)
+	for i, s := range m[1:] {
+		v, err := strconv.ParseUint(s, 16, 32)
+		if err != nil {
+			return Entry{}, fmt.Errorf("field %d (%q): %w", i, s, err)
+		}
+		fields[i] = v
+	}
+
+	return Entry{
+		Leaf:    uint32(fields[0]),
+		Subleaf: uint32(fields[1]),
+		EAX:     uint32(fields[2]),
+		EBX:     uint32(fields[3]),
+		ECX:     uint32(fields[4]),
+		EDX:     uint32(fields[5]),
+	}, nil
+}
+
+// WriteRawDump writes data as a single "CPU 0:" block in the same
+// `cpuid -r`-compatible format ParseRawDump reads, so a capture taken with
+// DumpRawDump can be handed to this package's own ParseRawDump or to other
+// tooling that expects the `cpuid -r` layout.
+func WriteRawDump(w io.Writer, data Data) error {
+	if _, err := fmt.Fprintln(w, "CPU 0:"); err != nil {
+		return fmt.Errorf("cpuid: write raw dump: %w", err)
+	}
+	for _, e := range data.Entries {
+		_, err := fmt.Fprintf(w, "   0x%08x 0x%02x: eax=0x%08x ebx=0x%08x ecx=0x%08x edx=0x%08x\n",
+			e.Leaf, e.Subleaf, e.EAX, e.EBX, e.ECX, e.EDX)
+		if err != nil {
+			return fmt.Errorf("cpuid: write raw dump: %w", err)
+		}
+	}
+	return nil
+}
+
+// DumpRawDump captures the running CPU's full leaf hierarchy the same way
+// CaptureData does and writes it to filename in `cpuid -r` text format
+// instead of CaptureData's JSON, for cross-machine comparison with tools
+// that only understand the raw format.
+func DumpRawDump(filename string) error {
+	data := captureData()
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cpuid: create raw dump file: %w", err)
+	}
+	defer file.Close()
+
+	return WriteRawDump(file, data)
+}
+
+// RawDataFromFile reads filename as a `cpuid -r`-format dump and wraps it
+// in a FileSource, the raw-text sibling of DataFromFile. The result works
+// with every helper that takes a Source (CPUIDFromSource,
+// IsFeatureSupportedFromSource, GetXSaveLayout, ListPMCEvents and the like);
+// the (offline, filename) helpers, BuildReport and Detect read JSON captures
+// only.
+func RawDataFromFile(filename string) (Source, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cpuid: open raw dump file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := ParseRawDump(file)
+	if err != nil {
+		return nil, err
+	}
+	return FileSource{Data: data}, nil
+}
@@ -0,0 +1,101 @@
+package cpuid
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRawDump(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Data
+		wantErr bool
+	}{
+		{
+			name: "single CPU block",
+			input: "CPU 0:\n" +
+				"   0x00000000 0x00: eax=0x0000000d ebx=0x756e6547 ecx=0x6c65746e edx=0x49656e69\n" +
+				"   0x00000001 0x00: eax=0x000806ea ebx=0x00100800 ecx=0x7ffafbbf edx=0xbfebfbff\n",
+			want: Data{Entries: []Entry{
+				{Leaf: 0, Subleaf: 0, EAX: 0xd, EBX: 0x756e6547, ECX: 0x6c65746e, EDX: 0x49656e69},
+				{Leaf: 1, Subleaf: 0, EAX: 0x000806ea, EBX: 0x00100800, ECX: 0x7ffafbbf, EDX: 0xbfebfbff},
+			}},
+		},
+		{
+			name: "non-matching header lines are skipped",
+			input: "CPU 0:\n" +
+				"some unrelated banner\n" +
+				"   0x00000000 0x00: eax=0x0000000d ebx=0x756e6547 ecx=0x6c65746e edx=0x49656e69\n",
+			want: Data{Entries: []Entry{
+				{Leaf: 0, Subleaf: 0, EAX: 0xd, EBX: 0x756e6547, ECX: 0x6c65746e, EDX: 0x49656e69},
+			}},
+		},
+		{
+			name: "a later CPU block overwrites rather than duplicates a leaf/subleaf",
+			input: "CPU 0:\n" +
+				"   0x00000001 0x00: eax=0x000806ea ebx=0x00100800 ecx=0x7ffafbbf edx=0xbfebfbff\n" +
+				"CPU 1:\n" +
+				"   0x00000001 0x00: eax=0x000806ec ebx=0x00200800 ecx=0x7ffafbbf edx=0xbfebfbff\n",
+			want: Data{Entries: []Entry{
+				{Leaf: 1, Subleaf: 0, EAX: 0x000806ec, EBX: 0x00200800, ECX: 0x7ffafbbf, EDX: 0xbfebfbff},
+			}},
+		},
+		{
+			name:  "empty input yields empty Data",
+			input: "",
+			want:  Data{},
+		},
+		{
+			name: "malformed hex field is an error",
+			input: "CPU 0:\n" +
+				"   0x00000001 0x00: eax=0xZZZZZZZZ ebx=0x00100800 ecx=0x7ffafbbf edx=0xbfebfbff\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRawDump(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRawDump() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRawDump() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRawDump() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRawDumpRoundTrip(t *testing.T) {
+	data := Data{Entries: []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 0xd, EBX: 0x756e6547, ECX: 0x6c65746e, EDX: 0x49656e69},
+		{Leaf: 4, Subleaf: 2, EAX: 0x1c004121, EBX: 0x01c0003f, ECX: 0x000001ff, EDX: 0},
+		{Leaf: 0x80000001, Subleaf: 0, EAX: 0, EBX: 0, ECX: 0x00000021, EDX: 0x28100800},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteRawDump(&buf, data); err != nil {
+		t.Fatalf("WriteRawDump() error: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "CPU 0:\n") {
+		t.Fatalf("WriteRawDump() output missing CPU 0 header: %q", buf.String())
+	}
+
+	got, err := ParseRawDump(&buf)
+	if err != nil {
+		t.Fatalf("ParseRawDump() of written dump error: %v", err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("round-trip = %+v, want %+v", got, data)
+	}
+}
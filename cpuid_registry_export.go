@@ -0,0 +1,268 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Format selects how MarshalReport serializes the feature registry.
+type Format string
+
+// Supported MarshalReport formats.
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// RegistryFeature is one feature bit's full exported detail within
+// MarshalReport's group -> feature -> RegistryFeature schema.
+type RegistryFeature struct {
+	Name            string `json:"name" yaml:"name"`
+	Description     string `json:"description" yaml:"description"`
+	CPUIDRef        string `json:"cpuid_ref" yaml:"cpuid_ref"`
+	Vendor          string `json:"vendor" yaml:"vendor"`
+	EquivalentGroup string `json:"equivalent_group,omitempty" yaml:"equivalent_group,omitempty"`
+	EquivalentBit   int    `json:"equivalent_bit" yaml:"equivalent_bit"`
+	Supported       bool   `json:"supported" yaml:"supported"`
+	RawBitValue     uint32 `json:"raw_bit_value" yaml:"raw_bit_value"`
+}
+
+// RegistryReport is group name -> feature name -> RegistryFeature, the
+// schema MarshalReport documents and DiffReports consumes. It's keyed by
+// group rather than flattened to a slice (see Report/FeatureReportEntry) so
+// a caller can diff or filter one group at a time without re-scanning the
+// whole document.
+type RegistryReport map[string]map[string]RegistryFeature
+
+// BuildRegistryReport walks cpuFeaturesList the same way BuildReport does,
+// grouping entries by the group field of each FeatureSet (e.g. "Basic
+// CPU", and any others registered) instead of flattening to a
+// slice, so the result matches the group->feature shape a cluster-inventory
+// tool or CI matrix wants to diff group-by-group.
+func BuildRegistryReport(opts ReportOptions) RegistryReport {
+	report := make(RegistryReport)
+
+	categories := make([]string, 0, len(cpuFeaturesList))
+	for category := range cpuFeaturesList {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		fs := cpuFeaturesList[category]
+		if opts.Group != "" && fs.group != opts.Group {
+			continue
+		}
+		if fs.condition != nil && !fs.condition(0) {
+			continue
+		}
+
+		a, b, c, d := CPUIDWithMode(fs.leaf, fs.subleaf, opts.Offline, opts.Filename)
+		var regValue uint32
+		switch fs.register {
+		case 0:
+			regValue = a
+		case 1:
+			regValue = b
+		case 2:
+			regValue = c
+		case 3:
+			regValue = d
+		}
+
+		bits := make([]int, 0, len(fs.features))
+		for bit := range fs.features {
+			bits = append(bits, bit)
+		}
+		sort.Ints(bits)
+
+		for _, bit := range bits {
+			f := fs.features[bit]
+			if opts.Vendor != "" && f.vendor != opts.Vendor {
+				continue
+			}
+
+			supported := (regValue>>uint(bit))&1 == 1
+			if opts.SupportedOnly && !supported {
+				continue
+			}
+
+			group := report[fs.group]
+			if group == nil {
+				group = make(map[string]RegistryFeature)
+				report[fs.group] = group
+			}
+			group[f.name] = RegistryFeature{
+				Name:            f.name,
+				Description:     f.description,
+				CPUIDRef:        cpuidRefString(fs.leaf, fs.subleaf, fs.register, bit, f.name),
+				Vendor:          f.vendor,
+				EquivalentGroup: f.equivalentFeatureName,
+				EquivalentBit:   f.equivalent,
+				Supported:       supported,
+				RawBitValue:     regValue,
+			}
+		}
+	}
+
+	return report
+}
+
+// cpuidRefString renders the standard "CPUID.<leaf>.<subleaf>:<REG>.<name>[bit N]"
+// reference string for a feature bit (leaf in hex), matching the format the
+// feature tables already spell out by hand (see cpuid_features_leaf7.go) so
+// a generated ref reads the same as a hand-written one.
+func cpuidRefString(leaf, subleaf uint32, register, bit int, name string) string {
+	return fmt.Sprintf("CPUID.%X.%d:%s.%s[bit %d]", leaf, subleaf, registerName(register), name, bit)
+}
+
+// registerName returns the register mnemonic for the 0=EAX,1=EBX,2=ECX,3=EDX
+// convention cpuFeaturesList uses throughout this package.
+func registerName(register int) string {
+	switch register {
+	case 0:
+		return "EAX"
+	case 1:
+		return "EBX"
+	case 2:
+		return "ECX"
+	default:
+		return "EDX"
+	}
+}
+
+// MarshalReport writes BuildRegistryReport(opts) to w in the requested
+// format.
+func MarshalReport(w io.Writer, opts ReportOptions, format Format) error {
+	report := BuildRegistryReport(opts)
+
+	switch format {
+	case FormatJSON, "":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case FormatYAML:
+		return writeRegistryYAML(w, report)
+	default:
+		return fmt.Errorf("cpuid: unknown format %q", format)
+	}
+}
+
+// writeRegistryYAML emits report as a minimal block-style YAML document.
+// There's no YAML dependency available to this module, so this walks the
+// fixed group->feature->RegistryFeature shape directly instead of a
+// general-purpose encoder.
+func writeRegistryYAML(w io.Writer, report RegistryReport) error {
+	groups := make([]string, 0, len(report))
+	for group := range report {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		if _, err := fmt.Fprintf(w, "%s:\n", group); err != nil {
+			return err
+		}
+
+		features := report[group]
+		names := make([]string, 0, len(features))
+		for name := range features {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			rf := features[name]
+			fmt.Fprintf(w, "  %s:\n", name)
+			fmt.Fprintf(w, "    name: %s\n", rf.Name)
+			fmt.Fprintf(w, "    description: %s\n", rf.Description)
+			fmt.Fprintf(w, "    cpuid_ref: %s\n", rf.CPUIDRef)
+			fmt.Fprintf(w, "    vendor: %s\n", rf.Vendor)
+			if rf.EquivalentGroup != "" {
+				fmt.Fprintf(w, "    equivalent_group: %s\n", rf.EquivalentGroup)
+				fmt.Fprintf(w, "    equivalent_bit: %d\n", rf.EquivalentBit)
+			}
+			fmt.Fprintf(w, "    supported: %t\n", rf.Supported)
+			fmt.Fprintf(w, "    raw_bit_value: %d\n", rf.RawBitValue)
+		}
+	}
+	return nil
+}
+
+// Change is one feature whose supported status or raw bit value differs
+// between two RegistryReports.
+type Change struct {
+	Group          string `json:"group"`
+	Feature        string `json:"feature"`
+	OldSupported   bool   `json:"old_supported"`
+	NewSupported   bool   `json:"new_supported"`
+	OldRawBitValue uint32 `json:"old_raw_bit_value"`
+	NewRawBitValue uint32 `json:"new_raw_bit_value"`
+}
+
+// DiffReports decodes two JSON documents written by MarshalReport (format
+// FormatJSON) and returns every feature whose Supported status or
+// RawBitValue differs between them -- a microcode/BIOS update flipping a
+// feature bit, or a group/feature present on one host and absent on the
+// other (which surfaces as a one-sided Change with the missing side
+// zero-valued), the same delta a MinIO OBD-style host report comparison
+// produces for cluster inventory or regression detection.
+func DiffReports(a, b io.Reader) ([]Change, error) {
+	var ra, rb RegistryReport
+	if err := json.NewDecoder(a).Decode(&ra); err != nil {
+		return nil, fmt.Errorf("cpuid: decode first report: %w", err)
+	}
+	if err := json.NewDecoder(b).Decode(&rb); err != nil {
+		return nil, fmt.Errorf("cpuid: decode second report: %w", err)
+	}
+
+	groupSet := make(map[string]bool)
+	for group := range ra {
+		groupSet[group] = true
+	}
+	for group := range rb {
+		groupSet[group] = true
+	}
+
+	var changes []Change
+	for _, group := range sortedStringSet(groupSet) {
+		featureSet := make(map[string]bool)
+		for feature := range ra[group] {
+			featureSet[feature] = true
+		}
+		for feature := range rb[group] {
+			featureSet[feature] = true
+		}
+
+		for _, feature := range sortedStringSet(featureSet) {
+			fa := ra[group][feature]
+			fb := rb[group][feature]
+			if fa.Supported == fb.Supported && fa.RawBitValue == fb.RawBitValue {
+				continue
+			}
+			changes = append(changes, Change{
+				Group:          group,
+				Feature:        feature,
+				OldSupported:   fa.Supported,
+				NewSupported:   fb.Supported,
+				OldRawBitValue: fa.RawBitValue,
+				NewRawBitValue: fb.RawBitValue,
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// sortedStringSet returns set's keys in sorted order.
+func sortedStringSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
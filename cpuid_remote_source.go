@@ -0,0 +1,36 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// RemoteSource replays a Data snapshot fetched from a URL, for comparing
+// feature sets from machines the caller doesn't have physical access to --
+// the same replay semantics as FileSource, just sourced over HTTP instead
+// of from local disk.
+type RemoteSource struct {
+	FileSource
+}
+
+// NewRemoteSource fetches and decodes the Data snapshot at url.
+func NewRemoteSource(url string) (RemoteSource, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return RemoteSource{}, fmt.Errorf("cpuid: fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return RemoteSource{}, fmt.Errorf("cpuid: fetch %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	var data Data
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return RemoteSource{}, fmt.Errorf("cpuid: decode snapshot from %s: %w", url, err)
+	}
+
+	return RemoteSource{FileSource{Data: data}}, nil
+}
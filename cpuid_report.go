@@ -0,0 +1,289 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ReportOptions controls which FeatureSets/features BuildReport includes and
+// how it derives supported status.
+type ReportOptions struct {
+	Offline  bool
+	Filename string
+
+	// Group, if non-empty, restricts the report to FeatureSets whose group
+	// matches exactly (e.g. "Basic CPU").
+	Group string
+	// Vendor, if non-empty, restricts the report to features tagged
+	// "amd", "intel", or "common" -- matching Feature.vendor.
+	Vendor string
+	// SupportedOnly drops every feature the running/captured CPU doesn't
+	// report as present.
+	SupportedOnly bool
+	// IncludeCPUFlag synthesizes a QEMU-style "-cpu" flag string from the
+	// supported features in the report.
+	IncludeCPUFlag bool
+	// IncludeHypervisor adds hypervisor detection and a HypervisorProfile
+	// to the report, so consumers can distinguish bare-metal from guest
+	// capability sets.
+	IncludeHypervisor bool
+}
+
+// FeatureReportEntry is one feature bit's full detail: where it lives,
+// whether this CPU has it, and its cross-vendor equivalent if one is known.
+type FeatureReportEntry struct {
+	Category      string `json:"category"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Group         string `json:"group"`
+	Vendor        string `json:"vendor"`
+	Supported     bool   `json:"supported"`
+	Equivalent    string `json:"equivalent,omitempty"`
+	RegisterValue uint32 `json:"register_value"`
+}
+
+// Report is the structured result of BuildReport. Detect additionally
+// populates the sections below BuildReport itself leaves zero -- vendor,
+// signature, brand, cache/TLB/address-size/hybrid info, and raw leaf dumps
+// -- so the same type serves both the feature-focused caller BuildReport
+// was written for and a full-detection caller that wants one self
+// contained value to marshal or diff.
+type Report struct {
+	Entries    []FeatureReportEntry `json:"entries"`
+	CPUFlag    string               `json:"cpu_flag,omitempty"`
+	Hypervisor *HypervisorInfo      `json:"hypervisor,omitempty"`
+	Virt       *HypervisorProfile   `json:"virtualization,omitempty"`
+
+	VendorID     string           `json:"vendor_id,omitempty" yaml:"vendor_id,omitempty"`
+	VendorName   string           `json:"vendor_name,omitempty" yaml:"vendor_name,omitempty"`
+	BrandString  string           `json:"brand_string,omitempty" yaml:"brand_string,omitempty"`
+	Signature    Signature        `json:"signature,omitempty" yaml:"signature,omitempty"`
+	AddressSizes AddressSizes     `json:"address_sizes,omitempty" yaml:"address_sizes,omitempty"`
+	Cache        []CPUCacheInfo   `json:"cache,omitempty" yaml:"cache,omitempty"`
+	TLB          *TLBInfo         `json:"tlb,omitempty" yaml:"tlb,omitempty"`
+	Hybrid       *IntelHybridInfo `json:"hybrid,omitempty" yaml:"hybrid,omitempty"`
+	RawLeaves    []RawLeaf        `json:"raw_leaves,omitempty" yaml:"raw_leaves,omitempty"`
+}
+
+// BuildReport walks cpuFeaturesList applying opts' filters, resolving each
+// feature's supported status and cross-vendor equivalent, and optionally
+// synthesizing a QEMU "-cpu" flag string from the result -- so the same
+// data this package's text/JSON output prints can drop straight into a
+// libvirt/QEMU config.
+func BuildReport(opts ReportOptions) Report {
+	var report Report
+
+	categories := make([]string, 0, len(cpuFeaturesList))
+	for category := range cpuFeaturesList {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		fs := cpuFeaturesList[category]
+		if opts.Group != "" && fs.group != opts.Group {
+			continue
+		}
+		if fs.condition != nil && !fs.condition(0) {
+			continue
+		}
+
+		a, b, c, d := CPUIDWithMode(fs.leaf, fs.subleaf, opts.Offline, opts.Filename)
+		var regValue uint32
+		switch fs.register {
+		case 0:
+			regValue = a
+		case 1:
+			regValue = b
+		case 2:
+			regValue = c
+		case 3:
+			regValue = d
+		}
+
+		bits := make([]int, 0, len(fs.features))
+		for bit := range fs.features {
+			bits = append(bits, bit)
+		}
+		sort.Ints(bits)
+
+		for _, bit := range bits {
+			f := fs.features[bit]
+			if opts.Vendor != "" && f.vendor != opts.Vendor {
+				continue
+			}
+
+			supported := (regValue>>uint(bit))&1 == 1
+			if opts.SupportedOnly && !supported {
+				continue
+			}
+
+			report.Entries = append(report.Entries, FeatureReportEntry{
+				Category:      category,
+				Name:          f.name,
+				Description:   f.description,
+				Group:         fs.group,
+				Vendor:        f.vendor,
+				Supported:     supported,
+				Equivalent:    resolveEquivalent(f),
+				RegisterValue: regValue,
+			})
+		}
+	}
+
+	if opts.IncludeCPUFlag {
+		report.CPUFlag = report.cpuFlagString()
+	}
+
+	if opts.IncludeHypervisor {
+		src, err := sourceFromMode(opts.Offline, opts.Filename)
+		if err == nil {
+			info := GetHypervisorInfoFromSource(src)
+			report.Hypervisor = &info
+			if info.Present {
+				profile := BuildHypervisorProfile(info, src)
+				report.Virt = &profile
+			}
+		}
+	}
+
+	return report
+}
+
+// resolveEquivalent looks up the human-readable name of f's cross-vendor
+// equivalent feature via equivalentFeatureName/equivalent, returning "" if
+// neither is set.
+func resolveEquivalent(f Feature) string {
+	if f.equivalentFeatureName == "" || f.equivalent < 0 {
+		return ""
+	}
+	other, ok := cpuFeaturesList[f.equivalentFeatureName]
+	if !ok {
+		return ""
+	}
+	eq, ok := other.features[f.equivalent]
+	if !ok {
+		return ""
+	}
+	return eq.name
+}
+
+// cpuFlagString synthesizes a QEMU-style "-cpu" flag string
+// ("qemu64,+avx2,+bmi1,...") from every supported feature in the report,
+// the way QEMU composes a named model plus "+feature" overrides.
+func (r Report) cpuFlagString() string {
+	var flags []string
+	seen := make(map[string]bool)
+	for _, e := range r.Entries {
+		if !e.Supported || seen[e.Name] {
+			continue
+		}
+		seen[e.Name] = true
+		flags = append(flags, "+"+strings.ToLower(e.Name))
+	}
+	sort.Strings(flags)
+	return strings.Join(append([]string{"qemu64"}, flags...), ",")
+}
+
+// MarshalJSON implements json.Marshaler. It exists only to give Report a
+// stable, documented JSON shape independent of field ordering/tags changing
+// on the underlying structs.
+func (r Report) MarshalJSON() ([]byte, error) {
+	type reportAlias Report
+	return json.Marshal(reportAlias(r))
+}
+
+// MarshalYAML renders r as a minimal block-style YAML document, reusing
+// json.Marshal to get Report's field names/omitempty rules right and then
+// reshaping the result into YAML -- this module has no YAML dependency
+// available to it, and Report's shape (structs, slices, scalars, no
+// anchors/multi-line strings) doesn't need a general-purpose encoder.
+func (r Report) MarshalYAML() ([]byte, error) {
+	raw, err := r.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("cpuid: marshal report to YAML: %w", err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("cpuid: marshal report to YAML: %w", err)
+	}
+
+	var b strings.Builder
+	encodeYAMLValue(&b, doc, 0)
+	return []byte(b.String()), nil
+}
+
+// encodeYAMLValue walks the generic JSON-shaped value doc (as produced by
+// json.Unmarshal into interface{}: map[string]interface{}, []interface{},
+// or a scalar) and writes it as block-style YAML.
+func encodeYAMLValue(b *strings.Builder, doc interface{}, indent int) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		encodeYAMLMap(b, v, indent)
+	case []interface{}:
+		encodeYAMLSlice(b, v, indent)
+	case string:
+		fmt.Fprintf(b, "%s\n", v)
+	case nil:
+		b.WriteString("null\n")
+	default:
+		fmt.Fprintf(b, "%v\n", v)
+	}
+}
+
+func encodeYAMLMap(b *strings.Builder, m map[string]interface{}, indent int) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pad := strings.Repeat("  ", indent)
+	for _, k := range keys {
+		v := m[k]
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			fmt.Fprintf(b, "%s%s:\n", pad, k)
+			encodeYAMLMap(b, vv, indent+1)
+		case []interface{}:
+			fmt.Fprintf(b, "%s%s:\n", pad, k)
+			encodeYAMLSlice(b, vv, indent+1)
+		default:
+			fmt.Fprintf(b, "%s%s: ", pad, k)
+			encodeYAMLValue(b, v, indent)
+		}
+	}
+}
+
+func encodeYAMLSlice(b *strings.Builder, s []interface{}, indent int) {
+	pad := strings.Repeat("  ", indent)
+	for _, v := range s {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			fmt.Fprintf(b, "%s- \n", pad)
+			encodeYAMLMap(b, vv, indent+1)
+		default:
+			fmt.Fprintf(b, "%s- ", pad)
+			encodeYAMLValue(b, v, indent)
+		}
+	}
+}
+
+// WriteNDJSON streams the report as newline-delimited JSON, one
+// FeatureReportEntry object per line, for shell pipelines and
+// configuration-management tools that want to filter/process one feature
+// at a time instead of parsing the whole Report.
+func (r Report) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, e := range r.Entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("cpuid: write NDJSON entry %q: %w", e.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// Source abstracts where CPUID register values come from, so the Get*/Is*
+// helpers can be driven by something other than the live CPUID instruction
+// or a JSON file on disk -- for example a fixture in a unit test, or a
+// remote telemetry agent that captured a snapshot on another machine.
+type Source interface {
+	CPUID(leaf, subleaf uint32) (a, b, c, d uint32)
+}
+
+// NativeSource reads CPUID straight from the hardware instruction.
+type NativeSource struct{}
+
+// CPUID implements Source.
+func (NativeSource) CPUID(leaf, subleaf uint32) (a, b, c, d uint32) {
+	return cpuid(leaf, subleaf)
+}
+
+// FileSource replays a previously captured Data snapshot (see CaptureData /
+// DataFromFile), matching entries by leaf and subleaf.
+type FileSource struct {
+	Data Data
+}
+
+// CPUID implements Source.
+func (s FileSource) CPUID(leaf, subleaf uint32) (a, b, c, d uint32) {
+	for _, e := range s.Data.Entries {
+		if e.Leaf == leaf && e.Subleaf == subleaf {
+			return e.EAX, e.EBX, e.ECX, e.EDX
+		}
+	}
+	return 0, 0, 0, 0
+}
+
+// MockSource is a fixed table of leaf/subleaf -> register values, intended
+// for unit tests that want to exercise the Get*/Is* helpers without
+// touching the filesystem or real hardware.
+type MockSource struct {
+	Entries map[In]Out
+}
+
+// NewMockSource builds a MockSource from a set of entries.
+func NewMockSource(entries map[In]Out) MockSource {
+	return MockSource{Entries: entries}
+}
+
+// CPUID implements Source.
+func (s MockSource) CPUID(leaf, subleaf uint32) (a, b, c, d uint32) {
+	out, ok := s.Entries[In{Leaf: leaf, Subleaf: subleaf}]
+	if !ok {
+		return 0, 0, 0, 0
+	}
+	return out.EAX, out.EBX, out.ECX, out.EDX
+}
+
+// sourceFromMode builds the Source that CPUIDWithMode would have used
+// internally for a given (offline, filename) pair: a FileSource when
+// offline is requested, otherwise the native instruction. Get*/Is* helpers
+// that want to accept a Source directly can use this to stay compatible
+// with the existing offline/filename API.
+func sourceFromMode(offline bool, filename string) (Source, error) {
+	if !offline {
+		return NativeSource{}, nil
+	}
+
+	data, err := DataFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return FileSource{Data: data}, nil
+}
+
+// CPUIDFromSource is the Source-based sibling of CPUIDWithMode: every
+// exported Get*/Is* helper that currently threads (offline bool, filename
+// string) can be given an equivalent that takes a Source instead, with the
+// old API delegating through sourceFromMode. This is also how the
+// CPUIDOverlay from cpuid_featureset.go becomes composable -- CPUIDOverlay
+// itself only needs to implement Source to be usable anywhere a Source is
+// accepted.
+func CPUIDFromSource(src Source, leaf, subleaf uint32) (a, b, c, d uint32) {
+	return src.CPUID(leaf, subleaf)
+}
+
+// CPUID implements Source for CPUIDOverlay, so overlays built in
+// cpuid_featureset.go can be passed anywhere a Source is accepted -- including
+// wrapping another Source by constructing the CPUIDOverlay's base Data from
+// it ahead of time.
+func (fset *CPUIDOverlay) CPUID(leaf, subleaf uint32) (a, b, c, d uint32) {
+	out := fset.Query(In{Leaf: leaf, Subleaf: subleaf})
+	return out.EAX, out.EBX, out.ECX, out.EDX
+}
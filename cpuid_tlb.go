@@ -3,9 +3,32 @@ package cpuid
 
 import (
 	"fmt"
-	"strings"
 )
 
+// TLBEntry is one decoded TLB descriptor: how many entries it has, the page
+// size(s) it covers, and its associativity.
+type TLBEntry struct {
+	PageSize      string
+	Entries       int
+	Associativity string
+}
+
+// TLBLevel is one cache-hierarchy level's TLB entries, split by the kind of
+// address translation they cover.
+type TLBLevel struct {
+	Data        []TLBEntry
+	Instruction []TLBEntry
+	Unified     []TLBEntry
+}
+
+// TLBInfo is the full decoded TLB hierarchy for a CPU, L1 through L3.
+type TLBInfo struct {
+	Vendor string
+	L1     TLBLevel
+	L2     TLBLevel
+	L3     TLBLevel
+}
+
 // GetTLBInfo returns TLB information for the CPU
 func GetTLBInfo(maxFunc, maxExtFunc uint32, offline bool, filename string) (TLBInfo, error) {
 	if isAMD(offline, filename) {
@@ -105,15 +128,16 @@ func GetIntelTLBInfo(maxFunc uint32, offline bool, filename string) TLBInfo {
 		return info
 	}
 
-	// Process traditional descriptors (leaf 0x2)
-	a, b, c, d := CPUIDWithMode(0x2, 0, offline, filename)
-	processIntelDescriptors(&info, a>>8, b, c, d)
+	// leaf 0x18 (structured TLB info) is authoritative when present; the
+	// leaf-2 descriptor scan only supplements it with entries leaf 0x18
+	// doesn't report, deduplicated by (level, type, entry) so a descriptor
+	// covering the same TLB leaf 0x18 already described isn't double-counted.
+	seen := make(map[leafTLBKey]bool)
 
-	// Process structured TLB information (leaf 0x18)
 	if maxFunc >= 0x18 {
 		subleaf := uint32(0)
 		for {
-			_, b, c, d = CPUIDWithMode(0x18, subleaf, offline, filename)
+			_, b, c, d := CPUIDWithMode(0x18, subleaf, offline, filename)
 
 			if (d & 0x1F) != 1 { // 1 indicates TLB entry
 				break
@@ -125,10 +149,9 @@ func GetIntelTLBInfo(maxFunc uint32, offline bool, filename string) TLBInfo {
 				Associativity: getIntelAssociativity(b >> 8),
 			}
 
-			level := (c >> 5) & 0x7
+			level := int((c >> 5) & 0x7)
 			tlbType := getTLBType((c >> 8) & 0x3)
 
-			// Add entry to appropriate level and type
 			switch level {
 			case 1:
 				addIntelTLBEntry(&info.L1, tlbType, entry)
@@ -137,14 +160,45 @@ func GetIntelTLBInfo(maxFunc uint32, offline bool, filename string) TLBInfo {
 			case 3:
 				addIntelTLBEntry(&info.L3, tlbType, entry)
 			}
+			seen[leafTLBKey{level: level, tlbType: tlbType, entry: entry}] = true
 
 			subleaf++
 		}
 	}
 
+	// Process traditional descriptors (leaf 0x2) via the shared SDM Vol.2
+	// Table 3-12 decoder, which also covers cache descriptors and the
+	// leaf-4/leaf-0x18 fallback sentinels.
+	decoded, _ := DecodeIntelLeaf2(offline, filename)
+	for _, t := range decoded.TLBs {
+		key := leafTLBKey{level: t.Level, tlbType: t.Type, entry: t.Entry}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		switch t.Level {
+		case 1:
+			addIntelTLBEntry(&info.L1, t.Type, t.Entry)
+		case 2:
+			addIntelTLBEntry(&info.L2, t.Type, t.Entry)
+		case 3:
+			addIntelTLBEntry(&info.L3, t.Type, t.Entry)
+		}
+	}
+
 	return info
 }
 
+// leafTLBKey dedups a TLB entry discovered from both leaf 0x18 and the leaf
+// 0x2 descriptor scan, so a CPU reporting both never double-counts the same
+// physical TLB.
+type leafTLBKey struct {
+	level   int
+	tlbType string
+	entry   TLBEntry
+}
+
 // getTLBPageSize converts Intel's page size value to a string description
 func getTLBPageSize(value uint32) string {
 	switch value & 0xF {
@@ -193,43 +247,6 @@ func getTLBType(value uint32) string {
 	}
 }
 
-// Helper function to process Intel descriptors and add them to TLBInfo
-func processIntelDescriptors(info *TLBInfo, bytes ...uint32) {
-	for _, val := range bytes {
-		if val == 0 {
-			continue
-		}
-
-		for i := 0; i < 4; i++ {
-			descriptor := (val >> (i * 8)) & 0xFF
-			if entry := parseIntelDescriptor(descriptor); entry != nil {
-				// Add entry to appropriate level and type based on descriptor
-				// This is a simplified version - you might want to add more complex parsing
-				if strings.Contains(entry.PageSize, "4KB") || strings.Contains(entry.PageSize, "4MB") {
-					info.L1.Data = append(info.L1.Data, *entry)
-				}
-			}
-		}
-	}
-}
-
-// Helper function to parse Intel descriptor into TLBEntry
-func parseIntelDescriptor(descriptor uint32) *TLBEntry {
-	// This is a simplified version - you would want to expand this map
-	descriptors := map[uint32]TLBEntry{
-		0x01: {PageSize: "4KB", Entries: 32, Associativity: "4-way"},
-		0x02: {PageSize: "4MB", Entries: 2, Associativity: "4-way"},
-		0x03: {PageSize: "4KB", Entries: 64, Associativity: "4-way"},
-		0x04: {PageSize: "4MB", Entries: 8, Associativity: "4-way"},
-		// Add more descriptors as needed
-	}
-
-	if entry, ok := descriptors[descriptor]; ok {
-		return &entry
-	}
-	return nil
-}
-
 // getAMDAssociativity converts AMD's associativity value to a string description
 func getAMDAssociativity(value uint32) string {
 	switch value {
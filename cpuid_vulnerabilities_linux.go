@@ -0,0 +1,23 @@
+//go:build linux
+
+package cpuid
+
+import (
+	"os"
+	"strings"
+)
+
+// readSysfsVulnerability reads /sys/devices/system/cpu/vulnerabilities/<name>,
+// trimmed of its trailing newline. ok is false if the file doesn't exist --
+// an older kernel that predates a given issue, or a non-x86 kernel.
+func readSysfsVulnerability(name string) (state string, ok bool) {
+	if name == "" {
+		return "", false
+	}
+
+	data, err := os.ReadFile("/sys/devices/system/cpu/vulnerabilities/" + name)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
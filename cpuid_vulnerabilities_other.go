@@ -0,0 +1,10 @@
+//go:build !linux
+
+package cpuid
+
+// readSysfsVulnerability always reports no data on non-Linux platforms --
+// there is no /sys/devices/system/cpu/vulnerabilities/* equivalent, so
+// VulnerabilityReport falls back to its CPUID-derived status alone.
+func readSysfsVulnerability(name string) (state string, ok bool) {
+	return "", false
+}
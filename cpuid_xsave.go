@@ -0,0 +1,171 @@
+// Package cpuid provides information about the CPU running the current program.
+package cpuid
+
+// XStateComponent is one bit position in XCR0 (user state) or IA32_XSS
+// (supervisor state), decoded from CPUID.0DH's per-component sub-leaves
+// (sub-leaf N for component N, N>=2).
+type XStateComponent struct {
+	// Component is the XSAVE state-component bit number: 0=x87, 1=SSE,
+	// 2=AVX, 3/4=MPX BNDREGS/BNDCSR, 5/6/7=AVX-512 Opmask/ZMM_Hi256/
+	// Hi16_ZMM, 8=PT, 9=PKRU, 10=PASID, 11=CET_U, 12=CET_S, 13=HDC,
+	// 14=UINTR, 15=LBR, 16=HWP, 17=AMX_TILECFG, 18=AMX_TILEDATA.
+	Component int
+	// Name is a human-readable label for Component, e.g. "AVX512-Opmask".
+	Name string
+	// Size is the component's save area size in bytes (sub-leaf N EAX).
+	Size uint32
+	// Offset is the component's byte offset into the standard-form XSAVE
+	// area (sub-leaf N EBX). Compact-form offsets are not reported here,
+	// since they depend on which other components are enabled, not just
+	// this one; CompactAreaSize packs the enabled set the same way.
+	Offset uint32
+	// IsSupervisor is true if the component is managed by IA32_XSS
+	// (supervisor state) rather than XCR0 (user state), per sub-leaf N
+	// ECX bit 0.
+	IsSupervisor bool
+	// AlignedTo64 is true if the component must be 64-byte aligned within
+	// a compacted XSAVE area, per sub-leaf N ECX bit 1.
+	AlignedTo64 bool
+	// XFDSupported is true if the component supports eXtended Feature
+	// Disable (IA32_XFD), per sub-leaf N ECX bit 2.
+	XFDSupported bool
+}
+
+// xStateComponentNames maps XSAVE component bit numbers to their SDM
+// Vol.1 Table 13-* names.
+var xStateComponentNames = map[int]string{
+	0:  "x87",
+	1:  "SSE",
+	2:  "AVX",
+	3:  "MPX-BNDREGS",
+	4:  "MPX-BNDCSR",
+	5:  "AVX512-Opmask",
+	6:  "ZMM_Hi256",
+	7:  "Hi16_ZMM",
+	8:  "PT",
+	9:  "PKRU",
+	10: "PASID",
+	11: "CET_U",
+	12: "CET_S",
+	13: "HDC",
+	14: "UINTR",
+	15: "LBR",
+	16: "HWP",
+	17: "AMX_TILECFG",
+	18: "AMX_TILEDATA",
+}
+
+// XSaveLayout is the decoded CPUID.0DH XSAVE state-component layout for
+// src: every component XCR0/IA32_XSS can enable, plus the standard- and
+// compact-form area sizes CPUID.0DH sub-leaves 0/1 report for the full
+// set of currently-enabled components.
+type XSaveLayout struct {
+	// Components lists every state component sub-leaves 2..63 report as
+	// present, ordered by Component number.
+	Components []XStateComponent
+	// StandardSize is CPUID.0DH sub-leaf 0 EBX: the standard-form XSAVE
+	// area size for the components currently enabled in XCR0.
+	StandardSize uint32
+	// MaxStandardSize is CPUID.0DH sub-leaf 0 ECX: the standard-form
+	// XSAVE area size for every component XCR0 could enable.
+	MaxStandardSize uint32
+	// CompactSize is CPUID.0DH sub-leaf 1 EBX: the compacted XSAVE area
+	// size for the components currently enabled in XCR0|IA32_XSS, when
+	// the compaction extensions (XSAVEC/XSAVES) are supported.
+	CompactSize uint32
+}
+
+// GetXSaveLayout walks CPUID.0DH sub-leaves 0..63 on src and decodes every
+// state component it reports, skipping every sub-leaf that reports zero
+// size -- sub-leaf N's EAX is 0 for every component number the CPU doesn't
+// implement.
+func GetXSaveLayout(src Source) XSaveLayout {
+	var layout XSaveLayout
+
+	eax0, ebx0, ecx0, _ := src.CPUID(0xD, 0)
+	if eax0 == 0 {
+		return layout
+	}
+	layout.StandardSize = ebx0
+	layout.MaxStandardSize = ecx0
+
+	_, ebx1, _, _ := src.CPUID(0xD, 1)
+	layout.CompactSize = ebx1
+
+	for component := 2; component < 64; component++ {
+		eax, ebx, ecx, _ := src.CPUID(0xD, uint32(component))
+		if eax == 0 {
+			continue
+		}
+
+		name := xStateComponentNames[component]
+		if name == "" {
+			name = "Unknown"
+		}
+
+		layout.Components = append(layout.Components, XStateComponent{
+			Component:    component,
+			Name:         name,
+			Size:         eax,
+			Offset:       ebx,
+			IsSupervisor: ecx&(1<<0) != 0,
+			AlignedTo64:  ecx&(1<<1) != 0,
+			XFDSupported: ecx&(1<<2) != 0,
+		})
+	}
+
+	return layout
+}
+
+// StandardAreaSize returns the standard-form XSAVE area size required to
+// hold every component set in mask (an XCR0|IA32_XSS-style bitmask),
+// taking the largest fixed offset+size among the enabled components (and at
+// least the legacy area plus XSAVE header) -- the same layout
+// XSAVE/XRSTOR use when compaction isn't active.
+func (l XSaveLayout) StandardAreaSize(mask uint64) uint32 {
+	var size uint32
+	for _, c := range l.Components {
+		if mask&(1<<uint(c.Component)) == 0 {
+			continue
+		}
+		if end := c.Offset + c.Size; end > size {
+			size = end
+		}
+	}
+	if size == 0 {
+		return 0
+	}
+	// Components 0/1 (x87/SSE) live in the legacy area ahead of the
+	// XSAVE header, which every standard-form area includes regardless
+	// of mask.
+	const legacyAndHeaderSize = 512 + 64
+	if size < legacyAndHeaderSize {
+		size = legacyAndHeaderSize
+	}
+	return size
+}
+
+// CompactAreaSize returns the compacted XSAVE area size required to hold
+// every component set in mask, packing enabled components back-to-back
+// (respecting each component's AlignedTo64 requirement) the way XSAVEC/
+// XSAVES lay out a compacted area instead of using each component's fixed
+// standard-form Offset.
+func (l XSaveLayout) CompactAreaSize(mask uint64) uint32 {
+	const legacyAndHeaderSize = 512 + 64
+	offset := uint32(legacyAndHeaderSize)
+
+	for _, c := range l.Components {
+		if mask&(1<<uint(c.Component)) == 0 {
+			continue
+		}
+		if c.AlignedTo64 {
+			offset = (offset + 63) &^ 63
+		}
+		offset += c.Size
+	}
+
+	if offset == legacyAndHeaderSize {
+		return 0
+	}
+	return offset
+}
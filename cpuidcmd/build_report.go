@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/earentir/cpuid"
+	"github.com/earentir/cpuid/cpuidcmd/internal/report"
+)
+
+// buildReport collects every section the CLI can print into a single
+// report.Report, so --format={text,json,yaml,prometheus} all render from
+// the same structured data instead of each format duplicating the
+// collection logic.
+func buildReport() report.Report {
+	processorInfo := cpuid.GetProcessorInfo(maxFunc, maxExtFunc, offlineData, filename)
+	processorModel := cpuid.GetModelData(offlineData, filename)
+
+	r := report.Report{
+		Basic: report.BasicInfo{
+			MaxStandardFunction:  maxFunc,
+			MaxExtendedFunction:  maxExtFunc,
+			VendorID:             cpuid.GetVendorID(offlineData, filename),
+			VendorName:           cpuid.GetVendorName(offlineData, filename),
+			BrandString:          cpuid.GetBrandString(maxExtFunc, offlineData, filename),
+			Family:               processorModel.FamilyID,
+			ExtendedFamily:       processorModel.ExtendedFamily,
+			Model:                processorModel.ModelID,
+			ExtendedModel:        processorModel.ExtendedModel,
+			SteppingID:           processorModel.SteppingID,
+			ProcessorType:        processorModel.ProcessorType,
+			MaxLogicalProcessors: processorInfo.MaxLogicalProcessors,
+			CoreCount:            processorInfo.CoreCount,
+			ThreadPerCore:        processorInfo.ThreadPerCore,
+		},
+	}
+
+	if cache {
+		if caches, err := cpuid.GetCacheInfo(maxFunc, maxExtFunc, vendorID, offlineData, filename); err == nil {
+			for _, c := range caches {
+				r.Cache = append(r.Cache, report.CacheLevel{
+					Level:           c.Level,
+					Type:            c.Type,
+					SizeKB:          c.SizeKB,
+					Ways:            c.Ways,
+					LineSizeBytes:   c.LineSizeBytes,
+					TotalSets:       c.TotalSets,
+					MaxCoresSharing: c.MaxCoresSharing,
+				})
+			}
+		}
+	}
+
+	if tlb {
+		if tlbInfo, err := cpuid.GetTLBInfo(maxFunc, maxExtFunc, offlineData, filename); err == nil {
+			r.TLB = &report.TLBReport{
+				Vendor: tlbInfo.Vendor,
+				L1:     toReportTLBLevel(tlbInfo.L1),
+				L2:     toReportTLBLevel(tlbInfo.L2),
+				L3:     toReportTLBLevel(tlbInfo.L3),
+			}
+		}
+	}
+
+	if hybrid {
+		h := cpuid.GetIntelHybrid(offlineData, filename)
+		r.Hybrid = &report.HybridInfo{
+			HybridCPU:     h.HybridCPU,
+			NativeModelID: h.NativeModelID,
+			CoreType:      h.CoreType,
+			CoreTypeName:  h.CoreTypeName,
+		}
+	}
+
+	if featurecategories || featurecategoriesdetails {
+		for _, catName := range cpuid.GetAllFeatureCategories() {
+			r.FeatureCategories = append(r.FeatureCategories, report.FeatureCategory{
+				Name:      catName,
+				Known:     cpuid.GetAllKnownFeatures(catName),
+				Supported: cpuid.GetSupportedFeatures(catName, offlineData, filename),
+			})
+		}
+	}
+
+	return r
+}
+
+func toReportTLBLevel(l cpuid.TLBLevel) report.TLBLevel {
+	return report.TLBLevel{
+		Data:        toReportTLBEntries(l.Data),
+		Instruction: toReportTLBEntries(l.Instruction),
+		Unified:     toReportTLBEntries(l.Unified),
+	}
+}
+
+func toReportTLBEntries(entries []cpuid.TLBEntry) []report.TLBEntry {
+	out := make([]report.TLBEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, report.TLBEntry{PageSize: e.PageSize, Entries: e.Entries, Associativity: e.Associativity})
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/earentir/cpuid"
+)
+
+// printConfidentialCompute prints cpuid.ConfidentialCompute's decoded
+// SEV/SEV-ES/SEV-SNP or TME/TDX platform parameters, plus the combined
+// cpuid.IsGuest classification.
+func printConfidentialCompute() {
+	src := sourceFor(offlineData, filename)
+	cc := cpuid.ConfidentialCompute(src)
+
+	if cc.AMD != nil {
+		a := cc.AMD
+		fmt.Printf("  SME=%v SEV=%v SEV-ES=%v SEV-SNP=%v VMPL=%v\n", a.SMESupported, a.SEVSupported, a.SEVESSupported, a.SEVSNPSupported, a.VMPLSupported)
+		fmt.Printf("  C-bit=%d phys-addr-reduction=%d encrypted-guests=%d min-sev-asid=%d vmpls=%d\n",
+			a.CBitPosition, a.PhysAddrReduction, a.NumEncryptedGuests, a.MinSEVASID, a.NumVMPLs)
+	}
+	if cc.Intel != nil {
+		i := cc.Intel
+		fmt.Printf("  TME=%v MK-TME=%v TDX-guest=%v TDX-module=%v\n", i.TMESupported, i.MKTMESupported, i.TDXGuest, i.TDXModulePresent)
+	}
+
+	fmt.Printf("  Guest: %s\n", cpuid.IsGuest(src))
+}
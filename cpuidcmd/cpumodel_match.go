@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/earentir/cpuid"
+	"github.com/earentir/cpuid/cpumodels"
+)
+
+// detectedFeatureSet builds the cpumodels.FeatureSet runRequire also builds,
+// factored out here so printMatchModel doesn't duplicate the detection loop.
+func detectedFeatureSet() cpumodels.FeatureSet {
+	fs := cpumodels.FeatureSet{
+		Vendor:  vendorID,
+		Present: make(map[string]bool),
+		ReadRegister: func(leaf, subleaf uint32, register int) (uint32, error) {
+			a, b, c, d := cpuid.CPUIDFromSource(cpuid.NativeSource{}, leaf, subleaf)
+			switch register {
+			case 0:
+				return a, nil
+			case 1:
+				return b, nil
+			case 2:
+				return c, nil
+			case 3:
+				return d, nil
+			default:
+				return 0, fmt.Errorf("invalid register index %d", register)
+			}
+		},
+	}
+
+	for _, category := range cpuid.GetAllFeatureCategories() {
+		for _, name := range cpuid.GetSupportedFeatures(category, offlineData, filename) {
+			fs.Present[name] = true
+		}
+	}
+	return fs
+}
+
+// printMatchModel prints the closest cpumodels.Models baseline this host
+// satisfies and its differential feature list, the way libvirt's
+// cpu_map.xml matching would before deciding whether a guest can migrate in.
+func printMatchModel() {
+	name, added, missing := cpumodels.MatchModel(detectedFeatureSet())
+	if name == "" {
+		fmt.Println("  No matching CPU model for this vendor")
+		os.Exit(1)
+	}
+
+	fmt.Printf("  Closest model: %s\n", name)
+	if len(missing) > 0 {
+		fmt.Printf("  Missing:       %s\n", strings.Join(missing, ", "))
+	}
+	if len(added) > 0 {
+		fmt.Printf("  Extra:         %s\n", strings.Join(added, ", "))
+	}
+}
+
+// printBaselineModels prints the feature-name intersection cpumodels.Baseline
+// computes across a comma-separated list of named models -- the safe floor
+// a live-migration target has to satisfy for every one of them.
+func printBaselineModels(arg string) {
+	names := strings.Split(arg, ",")
+	fs := cpumodels.Baseline(names...)
+
+	present := make([]string, 0, len(fs.Present))
+	for name := range fs.Present {
+		present = append(present, name)
+	}
+	sort.Strings(present)
+
+	if fs.Vendor != "" {
+		fmt.Printf("  Vendor:  %s\n", fs.Vendor)
+	}
+	fmt.Printf("  Baseline features: %s\n", strings.Join(present, ", "))
+}
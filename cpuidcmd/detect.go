@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+)
+
+// buildDetectReport runs cpuid.Detect with the same offline/filename mode
+// every other command-line flag uses.
+func buildDetectReport() *cpuid.Report {
+	return cpuid.Detect(cpuid.ReportOptions{Offline: offlineData, Filename: filename})
+}
+
+// printDetectReport prints cpuid.Detect's full structured report, honoring
+// -format the same way buildReport's text/json/yaml/prometheus path does,
+// except prometheus doesn't make sense for a tree this shape so it falls
+// back to JSON.
+func printDetectReport() {
+	r := buildDetectReport()
+
+	switch format {
+	case "yaml":
+		out, err := r.MarshalYAML()
+		if err != nil {
+			fmt.Println("Error rendering report:", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(out))
+	case "text":
+		fmt.Printf("Vendor: %s (%s)\n", r.VendorName, r.VendorID)
+		fmt.Printf("Brand: %s\n", r.BrandString)
+		fmt.Printf("Signature: family=%d model=%d stepping=%d\n", r.Signature.Family, r.Signature.Model, r.Signature.Stepping)
+		fmt.Printf("Address sizes: physical=%d linear=%d\n", r.AddressSizes.PhysicalBits, r.AddressSizes.LinearBits)
+		fmt.Printf("Cache levels: %d, TLB: %v, hybrid: %v, raw leaves: %d, features: %d\n",
+			len(r.Cache), r.TLB != nil, r.Hybrid != nil, len(r.RawLeaves), len(r.Entries))
+	default:
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			fmt.Println("Error rendering report:", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/earentir/cpuid"
+)
+
+// printHypervisorInfo prints whether this CPU is presenting itself as a
+// hypervisor guest and, if so, which nested-virtualization and TSC
+// guarantees the host is passing through -- the same distinction QEMU has
+// to reason about when constructing a guest's CPUID from the host's.
+func printHypervisorInfo(offline bool, filename string) {
+	info, ok := cpuid.Virtualized(offline, filename)
+	if !ok {
+		fmt.Println("  Not virtualized (bare metal, or hypervisor bit hidden)")
+		return
+	}
+
+	fmt.Printf("  Vendor:    %s\n", info.Vendor)
+	fmt.Printf("  Signature: %q\n", info.Signature)
+	fmt.Printf("  Max Leaf:  0x%08x\n", info.MaxLeaf)
+
+	profile := cpuid.BuildHypervisorProfile(info, sourceFor(offline, filename))
+	fmt.Printf("  Nested VMX:    %s\n", displayExposure(profile.NestedVMX))
+	fmt.Printf("  Nested SVM:    %s\n", displayExposure(profile.NestedSVM))
+	fmt.Printf("  Invariant TSC: %s\n", displayExposure(profile.InvariantTSC))
+	if profile.SEVSNP != "" {
+		fmt.Printf("  SEV-SNP:       %s\n", displayExposure(profile.SEVSNP))
+	}
+	for _, note := range profile.Notes {
+		fmt.Println("  -", note)
+	}
+}
+
+func displayExposure(e cpuid.FeatureExposure) string {
+	if e == "" {
+		return "unknown"
+	}
+	return string(e)
+}
+
+// sourceFor builds the cpuid.Source matching this CLI's offline/filename
+// flag pair, the way cpuid's own unexported sourceFromMode does internally
+// for the Get*/Is* helpers. When -read-raw is set, filename is parsed as a
+// `cpuid -r`-style raw text dump instead of CaptureData's JSON.
+func sourceFor(offline bool, filename string) cpuid.Source {
+	if !offline {
+		return cpuid.NativeSource{}
+	}
+	if readRaw {
+		src, err := cpuid.RawDataFromFile(filename)
+		if err != nil {
+			return cpuid.NativeSource{}
+		}
+		return src
+	}
+	data, err := cpuid.DataFromFile(filename)
+	if err != nil {
+		return cpuid.NativeSource{}
+	}
+	return cpuid.FileSource{Data: data}
+}
@@ -0,0 +1,15 @@
+package report
+
+import "encoding/json"
+
+type jsonRenderer struct{}
+
+// Render implements Renderer, producing an indented JSON document covering
+// every populated section of r.
+func (jsonRenderer) Render(r Report) (string, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
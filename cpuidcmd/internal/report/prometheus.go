@@ -0,0 +1,44 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type prometheusRenderer struct{}
+
+// Render implements Renderer, emitting gauge metrics in Prometheus text
+// exposition format so a node_exporter-style textfile collector can scrape
+// this binary's output directly.
+func (prometheusRenderer) Render(r Report) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "cpuid_cores_physical %d\n", r.Basic.CoreCount)
+	fmt.Fprintf(&b, "cpuid_threads_per_core %d\n", r.Basic.ThreadPerCore)
+	fmt.Fprintf(&b, "cpuid_max_logical_processors %d\n", r.Basic.MaxLogicalProcessors)
+
+	for _, c := range r.Cache {
+		fmt.Fprintf(&b, "cpuid_cache_size_kb{level=%q,type=%q} %d\n", strconv.Itoa(int(c.Level)), c.Type, c.SizeKB)
+	}
+
+	if r.Hybrid != nil && r.Hybrid.HybridCPU {
+		fmt.Fprintf(&b, "cpuid_hybrid_core_type{cpu=\"0\"} %d\n", r.Hybrid.CoreType)
+	}
+
+	for _, cat := range r.FeatureCategories {
+		supported := make(map[string]bool, len(cat.Supported))
+		for _, name := range cat.Supported {
+			supported[name] = true
+		}
+		for _, name := range cat.Known {
+			val := 0
+			if supported[name] {
+				val = 1
+			}
+			fmt.Fprintf(&b, "cpuid_feature_supported{name=%q,category=%q} %d\n", name, cat.Name, val)
+		}
+	}
+
+	return b.String(), nil
+}
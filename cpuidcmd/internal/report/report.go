@@ -0,0 +1,122 @@
+// Package report builds a structured snapshot of everything the cpuidcmd
+// CLI prints, and renders it in one of several machine-readable formats so
+// the tool can be consumed by automation instead of scraped as text.
+package report
+
+// Format selects how a Report is rendered.
+type Format string
+
+// Supported output formats.
+const (
+	FormatText       Format = "text"
+	FormatJSON       Format = "json"
+	FormatYAML       Format = "yaml"
+	FormatPrometheus Format = "prometheus"
+)
+
+// CacheLevel mirrors cpuid.CPUCacheInfo for the purposes of this report, so
+// the report package doesn't need to import the root module's internal
+// field layout directly.
+type CacheLevel struct {
+	Level           uint32 `json:"level" yaml:"level"`
+	Type            string `json:"type" yaml:"type"`
+	SizeKB          uint32 `json:"size_kb" yaml:"size_kb"`
+	Ways            uint32 `json:"ways" yaml:"ways"`
+	LineSizeBytes   uint32 `json:"line_size_bytes" yaml:"line_size_bytes"`
+	TotalSets       uint32 `json:"total_sets" yaml:"total_sets"`
+	MaxCoresSharing uint32 `json:"max_cores_sharing" yaml:"max_cores_sharing"`
+}
+
+// TLBEntry mirrors cpuid.TLBEntry.
+type TLBEntry struct {
+	PageSize      string `json:"page_size" yaml:"page_size"`
+	Entries       int    `json:"entries" yaml:"entries"`
+	Associativity string `json:"associativity" yaml:"associativity"`
+}
+
+// TLBLevel groups TLB entries by Data/Instruction/Unified.
+type TLBLevel struct {
+	Data        []TLBEntry `json:"data,omitempty" yaml:"data,omitempty"`
+	Instruction []TLBEntry `json:"instruction,omitempty" yaml:"instruction,omitempty"`
+	Unified     []TLBEntry `json:"unified,omitempty" yaml:"unified,omitempty"`
+}
+
+// BasicInfo mirrors what printBasicInfo prints.
+type BasicInfo struct {
+	MaxStandardFunction  uint32 `json:"max_standard_function" yaml:"max_standard_function"`
+	MaxExtendedFunction  uint32 `json:"max_extended_function" yaml:"max_extended_function"`
+	VendorID             string `json:"vendor_id" yaml:"vendor_id"`
+	VendorName           string `json:"vendor_name" yaml:"vendor_name"`
+	BrandString          string `json:"brand_string" yaml:"brand_string"`
+	Family               uint32 `json:"family" yaml:"family"`
+	ExtendedFamily       uint32 `json:"extended_family" yaml:"extended_family"`
+	Model                uint32 `json:"model" yaml:"model"`
+	ExtendedModel        uint32 `json:"extended_model" yaml:"extended_model"`
+	SteppingID           uint32 `json:"stepping_id" yaml:"stepping_id"`
+	ProcessorType        uint32 `json:"processor_type" yaml:"processor_type"`
+	MaxLogicalProcessors uint32 `json:"max_logical_processors" yaml:"max_logical_processors"`
+	CoreCount            uint32 `json:"core_count" yaml:"core_count"`
+	ThreadPerCore        uint32 `json:"thread_per_core" yaml:"thread_per_core"`
+}
+
+// HybridInfo mirrors cpuid.IntelHybridInfo.
+type HybridInfo struct {
+	HybridCPU     bool   `json:"hybrid_cpu" yaml:"hybrid_cpu"`
+	NativeModelID uint32 `json:"native_model_id,omitempty" yaml:"native_model_id,omitempty"`
+	CoreType      uint32 `json:"core_type,omitempty" yaml:"core_type,omitempty"`
+	CoreTypeName  string `json:"core_type_name,omitempty" yaml:"core_type_name,omitempty"`
+}
+
+// FeatureCategory is one category's known/supported feature names.
+type FeatureCategory struct {
+	Name      string   `json:"name" yaml:"name"`
+	Known     []string `json:"known" yaml:"known"`
+	Supported []string `json:"supported" yaml:"supported"`
+}
+
+// Report is the full structured document covering every section the CLI
+// can print.
+type Report struct {
+	Basic             BasicInfo         `json:"basic" yaml:"basic"`
+	Cache             []CacheLevel      `json:"cache,omitempty" yaml:"cache,omitempty"`
+	TLB               *TLBReport        `json:"tlb,omitempty" yaml:"tlb,omitempty"`
+	Hybrid            *HybridInfo       `json:"hybrid,omitempty" yaml:"hybrid,omitempty"`
+	FeatureCategories []FeatureCategory `json:"feature_categories,omitempty" yaml:"feature_categories,omitempty"`
+}
+
+// TLBReport mirrors cpuid.TLBInfo.
+type TLBReport struct {
+	Vendor string   `json:"vendor" yaml:"vendor"`
+	L1     TLBLevel `json:"l1" yaml:"l1"`
+	L2     TLBLevel `json:"l2" yaml:"l2"`
+	L3     TLBLevel `json:"l3" yaml:"l3"`
+}
+
+// Renderer turns a Report into its on-the-wire representation for one
+// format.
+type Renderer interface {
+	Render(r Report) (string, error)
+}
+
+// RendererFor returns the Renderer for the requested format, or an error if
+// the format is unknown.
+func RendererFor(format Format) (Renderer, error) {
+	switch format {
+	case FormatText, "":
+		return textRenderer{}, nil
+	case FormatJSON:
+		return jsonRenderer{}, nil
+	case FormatYAML:
+		return yamlRenderer{}, nil
+	case FormatPrometheus:
+		return prometheusRenderer{}, nil
+	default:
+		return nil, unknownFormatError(format)
+	}
+}
+
+type unknownFormatError Format
+
+func (e unknownFormatError) Error() string {
+	return "report: unknown format " + string(e)
+}
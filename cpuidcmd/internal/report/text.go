@@ -0,0 +1,49 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+type textRenderer struct{}
+
+// Render implements Renderer, reproducing the original ad-hoc human-readable
+// layout the CLI printed before --format existed.
+func (textRenderer) Render(r Report) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "CPU Information")
+	fmt.Fprintln(&b, "===============")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "Basic Info")
+	fmt.Fprintln(&b, "----------")
+	fmt.Fprintf(&b, "  CPUID Max Standard Function: %d\n", r.Basic.MaxStandardFunction)
+	fmt.Fprintf(&b, "  CPUID Max Extended Function: 0x%08x\n", r.Basic.MaxExtendedFunction)
+	fmt.Fprintf(&b, "  CPU Vendor ID:               %s\n", r.Basic.VendorID)
+	fmt.Fprintf(&b, "  CPU Vendor Name:             %s\n", r.Basic.VendorName)
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "  Brand String: %s\n", r.Basic.BrandString)
+	fmt.Fprintf(&b, "  Family: 0x%x, Model: 0x%x, Stepping: 0x%x\n", r.Basic.Family, r.Basic.Model, r.Basic.SteppingID)
+	fmt.Fprintf(&b, "  Cores: %d, Threads Per Core: %d\n", r.Basic.CoreCount, r.Basic.ThreadPerCore)
+
+	if len(r.Cache) > 0 {
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "Cache Info")
+		fmt.Fprintln(&b, "----------")
+		for _, c := range r.Cache {
+			fmt.Fprintf(&b, "  L%d %s Cache: %d KB, %d-way, %d byte lines\n", c.Level, c.Type, c.SizeKB, c.Ways, c.LineSizeBytes)
+		}
+	}
+
+	if r.Hybrid != nil {
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "Intel Hybrid Core Info")
+		fmt.Fprintln(&b, "----------------------")
+		fmt.Fprintf(&b, "  Hybrid CPU: %t\n", r.Hybrid.HybridCPU)
+		if r.Hybrid.HybridCPU {
+			fmt.Fprintf(&b, "  Core Type: %s\n", r.Hybrid.CoreTypeName)
+		}
+	}
+
+	return b.String(), nil
+}
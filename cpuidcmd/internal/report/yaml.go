@@ -0,0 +1,119 @@
+package report
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type yamlRenderer struct{}
+
+// Render implements Renderer. There's no YAML dependency available to this
+// module, so this walks the Report via reflection and emits a minimal
+// block-style YAML document -- enough for every field type Report actually
+// uses (structs, slices, strings, numbers, bools), not a general-purpose
+// encoder.
+func (yamlRenderer) Render(r Report) (string, error) {
+	var b strings.Builder
+	encodeYAMLValue(&b, reflect.ValueOf(r), 0)
+	return b.String(), nil
+}
+
+func encodeYAMLValue(b *strings.Builder, v reflect.Value, indent int) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			b.WriteString("null\n")
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		encodeYAMLStruct(b, v, indent)
+	case reflect.Slice, reflect.Array:
+		encodeYAMLSlice(b, v, indent)
+	default:
+		fmt.Fprintf(b, "%v\n", v.Interface())
+	}
+}
+
+func encodeYAMLStruct(b *strings.Builder, v reflect.Value, indent int) {
+	t := v.Type()
+	pad := strings.Repeat("  ", indent)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlFieldName(field)
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+
+		if isEmptyYAMLValue(fv) {
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.Struct:
+			fmt.Fprintf(b, "%s%s:\n", pad, name)
+			encodeYAMLStruct(b, fv, indent+1)
+		case reflect.Ptr, reflect.Interface:
+			if fv.IsNil() {
+				continue
+			}
+			fmt.Fprintf(b, "%s%s:\n", pad, name)
+			encodeYAMLValue(b, fv, indent+1)
+		case reflect.Slice, reflect.Array:
+			fmt.Fprintf(b, "%s%s:\n", pad, name)
+			encodeYAMLSlice(b, fv, indent+1)
+		default:
+			fmt.Fprintf(b, "%s%s: %s\n", pad, name, yamlScalar(fv))
+		}
+	}
+}
+
+func encodeYAMLSlice(b *strings.Builder, v reflect.Value, indent int) {
+	pad := strings.Repeat("  ", indent)
+	for i := 0; i < v.Len(); i++ {
+		ev := v.Index(i)
+		if ev.Kind() == reflect.Struct {
+			fmt.Fprintf(b, "%s- \n", pad)
+			encodeYAMLStruct(b, ev, indent+1)
+		} else {
+			fmt.Fprintf(b, "%s- %s\n", pad, yamlScalar(ev))
+		}
+	}
+}
+
+func yamlScalar(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+func yamlFieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	if tag == "" {
+		return strings.ToLower(f.Name)
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
+
+func isEmptyYAMLValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String:
+		return v.String() == ""
+	default:
+		return false
+	}
+}
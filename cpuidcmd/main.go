@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"github.com/earentir/cpuid"
+	"github.com/earentir/cpuid/cpuidcmd/internal/report"
+	"github.com/earentir/cpuid/cpumodels"
 )
 
 var (
@@ -21,6 +23,33 @@ var (
 	hybrid                   bool
 	featurecategories        bool
 	featurecategoriesdetails bool
+	captureAll               bool
+	cpuSelector              int
+	format                   string
+	requireExpr              string
+	equivalentOf             string
+	remoteURL                string
+	hypervisor               bool
+	migrateTarget            string
+	migratePolicy            string
+	modelName                string
+	translateExpr            string
+	x86Level                 bool
+	x86LevelReport           bool
+	pmcVendor                string
+	registryFormat           string
+	diffReportsArg           string
+	pmcCatalog               bool
+	matchModel               bool
+	baselineModels           string
+	topology                 bool
+	vulnFormat               string
+	rdtInfo                  bool
+	xsaveLayout              bool
+	confidentialCompute      bool
+	detect                   bool
+	dumpRaw                  bool
+	readRaw                  bool
 )
 
 func init() {
@@ -35,8 +64,35 @@ func main() {
 	flag.BoolVar(&cache, "cache", false, "Print cache information")
 	flag.BoolVar(&tlb, "tlb", false, "Print TLB information")
 	flag.BoolVar(&hybrid, "hybrid", false, "Print Intel Hybrid Core information")
+	flag.BoolVar(&x86Level, "x86-64-level", false, "Print the x86-64-vN psABI level (GOAMD64 style) this CPU satisfies")
+	flag.BoolVar(&x86LevelReport, "x86-64-level-report", false, "Print every x86-64-vN psABI level and which of its required features this CPU is missing")
 	flag.BoolVar(&featurecategories, "fcategories", false, "Print all available CPU feature categories")
 	flag.BoolVar(&featurecategoriesdetails, "fcategorieswithdetails", false, "Print all available CPU feature categories with details")
+	flag.BoolVar(&captureAll, "write-all-cpus", false, "Capture a per-logical-CPU CPUID snapshot and write to file")
+	flag.IntVar(&cpuSelector, "cpu", -1, "Print the snapshot for a single logical CPU from a --write-all-cpus capture")
+	flag.StringVar(&format, "format", "text", "Output format: text, json, yaml, or prometheus")
+	flag.StringVar(&requireExpr, "require", "", "Exit non-zero unless this feature-set expression evaluates true, e.g. 'AMX_TILE & AVX512_STATE'")
+	flag.StringVar(&equivalentOf, "equivalent", "", "Print every feature cross-vendor-equivalent to the named feature")
+	flag.StringVar(&remoteURL, "remote", "", "Fetch a CPUID snapshot from a URL (as written by -write) and print its supported features instead of probing local hardware")
+	flag.BoolVar(&hypervisor, "hypervisor", false, "Print hypervisor detection (KVM/Hyper-V/Xen/VMware) and nested-virtualization exposure")
+	flag.StringVar(&migrateTarget, "migrate-to", "", "Check live-migration compatibility from -filename's snapshot to this target snapshot file")
+	flag.StringVar(&migratePolicy, "migrate-policy", "strict", "Migration check policy: strict, mask-down, or sev-only")
+	flag.StringVar(&modelName, "model", "", "Print supported features as replayed from a named CPU model (e.g. IvyBridge, Zen3) instead of probing local hardware")
+	flag.StringVar(&translateExpr, "translate", "", "Translate a feature identifier to another vendor's name, e.g. 'WAITPKG=amd' (accepts /proc/cpuinfo, QEMU, or internal names)")
+	flag.StringVar(&pmcVendor, "pmc-events", "", "Print available performance-monitoring-counter events for this CPU ('intel' or 'amd')")
+	flag.StringVar(&registryFormat, "registry-export", "", "Print the full feature registry (group -> feature -> detail) in this format: json or yaml")
+	flag.StringVar(&diffReportsArg, "diff-reports", "", "Print the feature changes between two JSON registry exports, as 'a.json,b.json'")
+	flag.BoolVar(&pmcCatalog, "pmc-catalog", false, "Print the named PMU event catalog (EventSelect/UMask/Counter) available on this CPU")
+	flag.BoolVar(&matchModel, "match-model", false, "Print the closest known CPU model (libvirt cpu_map-style) this host satisfies")
+	flag.StringVar(&baselineModels, "baseline-models", "", "Print the feature intersection across a comma-separated list of named CPU models, e.g. 'Haswell,EPYC-Rome'")
+	flag.BoolVar(&topology, "topology", false, "Print per-logical-CPU topology placement, including hybrid P-core/E-core partitioning")
+	flag.StringVar(&vulnFormat, "vulnerabilities", "", "Print the speculative-execution vulnerability report in this format: text, json, or markdown")
+	flag.BoolVar(&rdtInfo, "rdt", false, "Print RDT/PQoS cache-allocation, MBA, and monitoring capacities, plus any active resctrl groups")
+	flag.BoolVar(&xsaveLayout, "xsave-layout", false, "Print the CPUID.0DH XSAVE state-component layout and standard/compact area sizes")
+	flag.BoolVar(&confidentialCompute, "confidential-compute", false, "Print SEV/SEV-ES/SEV-SNP or TME/TDX platform parameters and the combined guest classification")
+	flag.BoolVar(&detect, "detect", false, "Print the full structured cpuid.Detect() report (honors -format json|yaml|text)")
+	flag.BoolVar(&dumpRaw, "dump-raw", false, "Write this machine's CPUID leaves to -filename as a `cpuid -r`-style raw text dump")
+	flag.BoolVar(&readRaw, "read-raw", false, "With -read, treat -filename as a `cpuid -r`-style raw text dump instead of JSON")
 
 	flag.StringVar(&filename, "filename", "cpuid_data.json", "Set the filename for read/write operations")
 	flag.Parse()
@@ -58,6 +114,138 @@ func main() {
 		os.Exit(0)
 	}
 
+	if dumpRaw {
+		fmt.Println("Writing raw CPUID dump to file")
+		fmt.Println("-------------------------------")
+		if err := cpuid.DumpRawDump(filename); err != nil {
+			fmt.Println("Error writing raw dump:", err)
+			os.Exit(1)
+		}
+		fmt.Println(" ", filename)
+		fmt.Println()
+		os.Exit(0)
+	}
+
+	if captureAll {
+		fmt.Println("Capturing per-CPU CPUID snapshot")
+		fmt.Println("---------------------------------")
+		writeAllCPUsToFile()
+		fmt.Println()
+		os.Exit(0)
+	}
+
+	if cpuSelector >= 0 {
+		printCPUSnapshot(cpuSelector)
+		fmt.Println()
+		os.Exit(0)
+	}
+
+	if translateExpr != "" {
+		printTranslation(translateExpr)
+		os.Exit(0)
+	}
+
+	if modelName != "" {
+		printModelFeatures(modelName)
+		os.Exit(0)
+	}
+
+	if pmcVendor != "" {
+		printPMCEvents(pmcVendor)
+		os.Exit(0)
+	}
+
+	if registryFormat != "" {
+		printRegistryExport(registryFormat)
+		os.Exit(0)
+	}
+
+	if diffReportsArg != "" {
+		printReportDiff(diffReportsArg)
+		os.Exit(0)
+	}
+
+	if pmcCatalog {
+		printPMCCatalog()
+		os.Exit(0)
+	}
+
+	if matchModel {
+		printMatchModel()
+		os.Exit(0)
+	}
+
+	if baselineModels != "" {
+		printBaselineModels(baselineModels)
+		os.Exit(0)
+	}
+
+	if topology {
+		printTopology()
+		os.Exit(0)
+	}
+
+	if vulnFormat != "" {
+		printVulnerabilities(vulnFormat)
+		os.Exit(0)
+	}
+
+	if rdtInfo {
+		printRDT()
+		os.Exit(0)
+	}
+
+	if xsaveLayout {
+		printXSaveLayout()
+		os.Exit(0)
+	}
+
+	if confidentialCompute {
+		printConfidentialCompute()
+		os.Exit(0)
+	}
+
+	if detect {
+		printDetectReport()
+		os.Exit(0)
+	}
+
+	if migrateTarget != "" {
+		printMigrationReport(filename, migrateTarget, migratePolicy)
+		os.Exit(0)
+	}
+
+	if remoteURL != "" {
+		printRemoteFeatures(remoteURL)
+		os.Exit(0)
+	}
+
+	if equivalentOf != "" {
+		for _, eq := range cpuid.Equivalents(equivalentOf) {
+			fmt.Printf("%s (%s): %s\n", eq.Name(), eq.Vendor(), eq.Description())
+		}
+		os.Exit(0)
+	}
+
+	if requireExpr != "" {
+		runRequire(requireExpr)
+	}
+
+	if format != "text" {
+		renderer, err := report.RendererFor(report.Format(format))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		out, err := renderer.Render(buildReport())
+		if err != nil {
+			fmt.Println("Error rendering report:", err)
+			os.Exit(1)
+		}
+		fmt.Println(out)
+		os.Exit(0)
+	}
+
 	fmt.Println("offlineData:", offlineData)
 	fmt.Println("filename:", filename)
 	fmt.Println()
@@ -92,6 +280,33 @@ func main() {
 		fmt.Println()
 	}
 
+	if x86Level {
+		fmt.Println("x86-64 psABI Level")
+		fmt.Println("------------------")
+		fmt.Println(" ", cpuid.ClassifyX86_64Level(sourceFor(offlineData, filename)))
+		fmt.Println()
+	}
+
+	if x86LevelReport {
+		fmt.Println("x86-64 psABI Level Report")
+		fmt.Println("-------------------------")
+		for _, gap := range cpuid.X86_64LevelReport(sourceFor(offlineData, filename)) {
+			if len(gap.Missing) == 0 {
+				fmt.Printf("  %s: satisfied\n", gap.Name)
+				continue
+			}
+			fmt.Printf("  %s: missing %v\n", gap.Name, gap.Missing)
+		}
+		fmt.Println()
+	}
+
+	if hypervisor {
+		fmt.Println("Hypervisor Info")
+		fmt.Println("---------------")
+		printHypervisorInfo(offlineData, filename)
+		fmt.Println()
+	}
+
 	if featurecategories {
 		fmt.Println("All Available CPU Feature Categories")
 		fmt.Println("------------------------------------")
@@ -140,6 +355,28 @@ func writeCPUIDToFile() {
 	fmt.Println("CPUID data captured successfully and written to cpuid_data.json.")
 }
 
+func writeAllCPUsToFile() {
+	if err := cpuid.CaptureAllCPUs(filename); err != nil {
+		fmt.Println("Error capturing per-CPU CPUID data:", err)
+		return
+	}
+	fmt.Printf("Per-CPU CPUID data captured successfully and written to %s.\n", filename)
+}
+
+func printCPUSnapshot(cpu int) {
+	entry, err := cpuid.CPUSnapshot(filename, cpu)
+	if err != nil {
+		fmt.Println("Failed to read CPU snapshot:", err)
+		return
+	}
+	fmt.Printf("  CPU:         %d\n", entry.CPU)
+	fmt.Printf("  APIC ID:     %d\n", entry.APICID)
+	fmt.Printf("  Core Type:   %s\n", entry.CoreType)
+	fmt.Printf("  Core ID:     %d\n", entry.CoreID)
+	fmt.Printf("  Physical ID: %d\n", entry.PhysID)
+	fmt.Printf("  Entries:     %d\n", len(entry.Entries))
+}
+
 func printBasicInfo() {
 	processorInfo := cpuid.GetProcessorInfo(maxFunc, maxExtFunc, offlineData, filename)
 	processorModel := cpuid.GetModelData(offlineData, filename)
@@ -172,6 +409,16 @@ func printBasicInfo() {
 	fmt.Printf("  Linear Address Bits: %d\n", processorInfo.LinearAddressBits)
 	fmt.Printf("  Cores: %d\n", processorInfo.CoreCount)
 	fmt.Printf("  Threads Per Core: %d\n", processorInfo.ThreadPerCore)
+
+	fmt.Println()
+
+	if name, _, missing := cpumodels.MatchModel(detectedFeatureSet()); name != "" {
+		fmt.Printf("  Closest Known Model: %s", name)
+		if len(missing) > 0 {
+			fmt.Printf(" (missing %d features)", len(missing))
+		}
+		fmt.Println()
+	}
 }
 
 func printCacheInfo() {
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+)
+
+// printMigrationReport loads the CPUID snapshots at sourceFile/targetFile
+// (as written by -write) and prints the MigrationCompatible report between
+// them, the practical check this library exists to automate before a QEMU
+// live migration.
+func printMigrationReport(sourceFile, targetFile, policyFlag string) {
+	source, err := cpuid.DataFromFile(sourceFile)
+	if err != nil {
+		fmt.Println("Failed to read source snapshot:", err)
+		os.Exit(1)
+	}
+	target, err := cpuid.DataFromFile(targetFile)
+	if err != nil {
+		fmt.Println("Failed to read target snapshot:", err)
+		os.Exit(1)
+	}
+
+	policy := cpuid.PolicyStrict
+	switch policyFlag {
+	case "mask-down":
+		policy = cpuid.PolicyMaskDown
+	case "sev-only":
+		policy = cpuid.PolicySEVOnly
+	case "strict", "":
+		policy = cpuid.PolicyStrict
+	default:
+		fmt.Println("Unknown -migrate-policy:", policyFlag)
+		os.Exit(1)
+	}
+
+	r := cpuid.MigrationCompatible(source, target, policy)
+	fmt.Printf("  Policy:      %s\n", r.Policy)
+	fmt.Printf("  Compatible:  %t\n", r.Compatible)
+	if len(r.FatalMissing) > 0 {
+		fmt.Println("  Fatal (missing on target):", r.FatalMissing)
+	}
+	if len(r.BenignExtra) > 0 {
+		fmt.Println("  Benign (target only):", r.BenignExtra)
+	}
+	if len(r.MaskRequired) > 0 {
+		fmt.Println("  Mask required:", r.MaskRequired)
+	}
+	if r.TopologyMismatch != "" {
+		fmt.Println("  Topology mismatch:", r.TopologyMismatch)
+	}
+	if len(r.MitigationDelta) > 0 {
+		fmt.Println("  Mitigation delta:", r.MitigationDelta)
+	}
+	fmt.Printf("  XSAVE area: source=%d target=%d bytes\n", r.XSaveArea.SourceSize, r.XSaveArea.TargetSize)
+
+	if !r.Compatible {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+)
+
+// printModelFeatures replays a named CPU model's CPUID table and prints its
+// supported features category by category, the same "would this binary run
+// on <model>" report printRemoteFeatures gives for a fetched snapshot.
+func printModelFeatures(name string) {
+	snap, err := cpuid.LoadModel(name)
+	if err != nil {
+		fmt.Println("Failed to load CPU model:", err)
+		fmt.Println("Known models:", cpuid.ModelNames())
+		os.Exit(1)
+	}
+
+	for _, category := range cpuid.GetAllFeatureCategories() {
+		supported := cpuid.GetSupportedFeaturesFromSource(category, snap)
+		if len(supported) == 0 {
+			continue
+		}
+		fmt.Printf("%s:\n", category)
+		for _, f := range supported {
+			fmt.Println(" -", f)
+		}
+	}
+}
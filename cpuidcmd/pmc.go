@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+	"github.com/earentir/cpuid/pmc"
+)
+
+// printPMCEvents prints every performance-monitoring-counter event this
+// CPU's feature bits make usable for the given vendor, the same gate
+// pmc.Open applies before calling perf_event_open/hwpmc(4).
+func printPMCEvents(vendor string) {
+	src := sourceFor(offlineData, filename)
+
+	info := pmc.GetPerfMonInfo(src)
+	if info.Version > 0 {
+		fmt.Printf("  Arch perfmon version %d: %d general counters (%d-bit), %d fixed counters (%d-bit)\n",
+			info.Version, info.NumGeneralCounters, info.GeneralCounterWidth, info.NumFixedCounters, info.FixedCounterWidth)
+	}
+
+	events := pmc.Events(src, vendor)
+	if len(events) == 0 {
+		fmt.Printf("  No %s PMC events available on this CPU\n", vendor)
+		os.Exit(1)
+	}
+	for _, ev := range events {
+		fmt.Printf("  %-16s %s (%s)\n", ev.Name, ev.Description, ev.CPUIDRef)
+	}
+}
+
+// printPMCCatalog prints every named PMU event cpuid.ListPMCEvents finds
+// available on this CPU, with its raw EventSelect/UMask/Counter tuple --
+// the encoding a caller would program into IA32_PERFEVTSELx or the AMD
+// equivalent MSR without hardcoding it per microarchitecture.
+func printPMCCatalog() {
+	src := sourceFor(offlineData, filename)
+
+	events := cpuid.ListPMCEvents(src)
+	if len(events) == 0 {
+		fmt.Println("  No named PMU events available on this CPU")
+		os.Exit(1)
+	}
+	for _, ev := range events {
+		fmt.Printf("  %-28s EventSelect=0x%02X UMask=0x%02X counter=%-7s %s\n",
+			ev.Name, ev.EventSelect, ev.UMask, ev.Counter, ev.Description)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid/rdt"
+)
+
+// printRDT prints the CAT/MBA/CMT/MBM capacities this CPU advertises, the
+// same decode rdt.GetCAT/GetMBA/GetMonitoring perform before a caller
+// drives resctrl with them.
+func printRDT() {
+	src := sourceFor(offlineData, filename)
+
+	for _, level := range []int{3, 2} {
+		cat, ok := rdt.GetCAT(src, level)
+		if !ok {
+			continue
+		}
+		fmt.Printf("  L%d CAT: mask-length=%d cdp=%v highest-cos=%d\n", cat.Level, cat.MaskLength, cat.CDPSupported, cat.HighestCOS)
+	}
+
+	if mba, ok := rdt.GetMBA(src); ok {
+		fmt.Printf("  MBA: max-delay=%d linear=%v highest-cos=%d\n", mba.MaxDelay, mba.LinearResponse, mba.HighestCOS)
+	}
+
+	if mon, ok := rdt.GetMonitoring(src); ok {
+		fmt.Printf("  Monitoring: max-rmid=%d conversion-factor=%d occupancy=%v total-bw=%v local-bw=%v\n",
+			mon.MaxRMID, mon.ConversionFactor, mon.L3OccupancySupported, mon.L3TotalBWSupported, mon.L3LocalBWSupported)
+	}
+
+	if !rdt.IsAvailable() {
+		fmt.Println("  resctrl filesystem not mounted; allocation/monitoring groups unavailable")
+		return
+	}
+	groups, err := rdt.Groups()
+	if err != nil {
+		fmt.Println("Error listing resctrl groups:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("  resctrl groups: %v\n", groups)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/earentir/cpuid"
+)
+
+// printRegistryExport writes the full feature registry (cpuid.MarshalReport)
+// to stdout in the requested format.
+func printRegistryExport(format string) {
+	opts := cpuid.ReportOptions{Offline: offlineData, Filename: filename}
+	if err := cpuid.MarshalReport(os.Stdout, opts, cpuid.Format(format)); err != nil {
+		fmt.Println("Error exporting registry:", err)
+		os.Exit(1)
+	}
+}
+
+// printReportDiff parses arg as "a.json,b.json", decodes both as
+// cpuid.MarshalReport JSON output, and prints every feature cpuid.DiffReports
+// found changed between them.
+func printReportDiff(arg string) {
+	a, b, ok := strings.Cut(arg, ",")
+	if !ok {
+		fmt.Println("Expected -diff-reports 'a.json,b.json'")
+		os.Exit(1)
+	}
+
+	fa, err := os.Open(a)
+	if err != nil {
+		fmt.Println("Error opening first report:", err)
+		os.Exit(1)
+	}
+	defer fa.Close()
+
+	fb, err := os.Open(b)
+	if err != nil {
+		fmt.Println("Error opening second report:", err)
+		os.Exit(1)
+	}
+	defer fb.Close()
+
+	changes, err := cpuid.DiffReports(fa, fb)
+	if err != nil {
+		fmt.Println("Error diffing reports:", err)
+		os.Exit(1)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("  No differences")
+		return
+	}
+	for _, c := range changes {
+		fmt.Printf("  %s/%s: supported %t -> %t, raw_bit_value 0x%x -> 0x%x\n",
+			c.Group, c.Feature, c.OldSupported, c.NewSupported, c.OldRawBitValue, c.NewRawBitValue)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+)
+
+// printRemoteFeatures fetches a CPUID snapshot from url and prints the
+// supported features in every category, the same way getAllSupportedFeaturesCategory
+// does for local hardware -- letting `cpuid --remote https://host/snapshot.json`
+// compare a machine the caller doesn't have physical access to.
+func printRemoteFeatures(url string) {
+	src, err := cpuid.NewRemoteSource(url)
+	if err != nil {
+		fmt.Println("Failed to fetch remote CPUID snapshot:", err)
+		os.Exit(1)
+	}
+
+	for _, category := range cpuid.GetAllFeatureCategories() {
+		supported := cpuid.GetSupportedFeaturesFromSource(category, src)
+		if len(supported) == 0 {
+			continue
+		}
+		fmt.Printf("%s:\n", category)
+		for _, f := range supported {
+			fmt.Println(" -", f)
+		}
+	}
+}
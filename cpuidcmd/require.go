@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+	"github.com/earentir/cpuid/cpumodels"
+)
+
+// runRequire evaluates expr against the detected CPU and exits the process:
+// 0 if it's satisfied, 1 if it isn't or the expression is malformed. It
+// exists so deployments/CI can gate on precise capability sets with
+// `cpuid --require 'AMX_TILE & AVX512_STATE'` instead of scraping text
+// output.
+func runRequire(expr string) {
+	detected := cpumodels.FeatureSet{
+		Vendor:  vendorID,
+		Present: make(map[string]bool),
+		ReadRegister: func(leaf, subleaf uint32, register int) (uint32, error) {
+			a, b, c, d := cpuid.CPUIDFromSource(cpuid.NativeSource{}, leaf, subleaf)
+			switch register {
+			case 0:
+				return a, nil
+			case 1:
+				return b, nil
+			case 2:
+				return c, nil
+			case 3:
+				return d, nil
+			default:
+				return 0, fmt.Errorf("invalid register index %d", register)
+			}
+		},
+	}
+
+	for _, category := range cpuid.GetAllFeatureCategories() {
+		for _, name := range cpuid.GetSupportedFeatures(category, offlineData, filename) {
+			detected.Present[name] = true
+		}
+	}
+
+	ok, err := cpumodels.Eval(expr, detected)
+	if err != nil {
+		fmt.Println("Error evaluating --require expression:", err)
+		os.Exit(1)
+	}
+	if !ok {
+		fmt.Println("Required feature expression not satisfied:", expr)
+		os.Exit(1)
+	}
+
+	fmt.Println("Required feature expression satisfied:", expr)
+	os.Exit(0)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+)
+
+// printTopology prints cpuid.EnumerateLogicalCPUs()'s per-logical-CPU placement plus
+// the P-core/E-core partition cpuid.HybridPartition derives from it.
+func printTopology() {
+	cpus, err := cpuid.EnumerateLogicalCPUs()
+	if err != nil {
+		fmt.Println("Error reading topology:", err)
+		os.Exit(1)
+	}
+
+	for _, lc := range cpus {
+		fmt.Printf("  CPU %-3d apic=0x%03x pkg=%d die=%d module=%d core=%d thread=%d",
+			lc.CPU, lc.APICID, lc.PackageID, lc.DieID, lc.ModuleID, lc.CoreID, lc.ThreadID)
+		if lc.CoreType != "" {
+			fmt.Printf(" type=%s eff_class=%d", lc.CoreType, lc.EfficiencyClass)
+		}
+		fmt.Println()
+	}
+
+	pCores, eCores, err := cpuid.HybridPartition()
+	if err != nil {
+		fmt.Println("Error partitioning hybrid cores:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("  P-cores: %v\n", pCores)
+	if len(eCores) > 0 {
+		fmt.Printf("  E-cores: %v\n", eCores)
+	}
+}
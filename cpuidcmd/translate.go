@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/earentir/cpuid"
+)
+
+// printTranslation parses expr as "name=vendor" and prints the result of
+// cpuid.Translate, plus every known alias for the canonicalized name, so
+// `cpuid --translate 'WAITPKG=amd'` tells the caller both the AMD
+// equivalent and every spelling of the original feature this package
+// recognizes.
+func printTranslation(expr string) {
+	name, vendor, ok := strings.Cut(expr, "=")
+	if !ok {
+		fmt.Println("Expected -translate 'name=vendor', e.g. 'WAITPKG=amd'")
+		os.Exit(1)
+	}
+
+	canon := cpuid.Canonicalize(name)
+	fmt.Printf("  Canonical: %s\n", canon)
+	fmt.Printf("  Aliases:   %v\n", cpuid.Aliases(canon))
+
+	translated, err := cpuid.Translate(name, vendor)
+	if err != nil {
+		fmt.Println("  Translate:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("  %s equivalent: %s\n", vendor, translated)
+}
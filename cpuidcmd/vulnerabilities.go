@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/earentir/cpuid"
+)
+
+// printVulnerabilities prints cpuid.VulnerabilityReport() in the requested
+// format: text (a plain table), json, or markdown.
+func printVulnerabilities(format string) {
+	report := cpuid.VulnerabilityReport()
+
+	switch format {
+	case "json":
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			fmt.Println("Error encoding vulnerability report:", err)
+			os.Exit(1)
+		}
+	case "markdown":
+		fmt.Print(report.Markdown())
+	case "text":
+		for _, f := range report.Findings {
+			fmt.Printf("  %-16s %s\n", f.Issue, f.Status)
+			if f.SysfsState != "" {
+				fmt.Printf("    sysfs: %s\n", f.SysfsState)
+			}
+			if f.Note != "" {
+				fmt.Printf("    note:  %s\n", f.Note)
+			}
+		}
+	default:
+		fmt.Println("Error: unknown vulnerability report format:", format)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/earentir/cpuid"
+)
+
+// printXSaveLayout prints the CPUID.0DH XSAVE state-component layout
+// cpuid.GetXSaveLayout decodes, along with the standard/compact area
+// sizes for the fully-enabled mask XCR0|IA32_XSS would report.
+func printXSaveLayout() {
+	src := sourceFor(offlineData, filename)
+	layout := cpuid.GetXSaveLayout(src)
+
+	fmt.Printf("  Standard area: %d bytes (max %d)\n", layout.StandardSize, layout.MaxStandardSize)
+	if layout.CompactSize > 0 {
+		fmt.Printf("  Compact area: %d bytes\n", layout.CompactSize)
+	}
+
+	var fullMask uint64
+	for _, c := range layout.Components {
+		supervisor := ""
+		if c.IsSupervisor {
+			supervisor = " supervisor"
+		}
+		fmt.Printf("  [%2d] %-14s size=%-6d offset=%-6d%s\n", c.Component, c.Name, c.Size, c.Offset, supervisor)
+		fullMask |= 1 << uint(c.Component)
+	}
+
+	fmt.Printf("  Full-mask standard size: %d bytes\n", layout.StandardAreaSize(fullMask))
+	fmt.Printf("  Full-mask compact size: %d bytes\n", layout.CompactAreaSize(fullMask))
+}
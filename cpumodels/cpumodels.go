@@ -0,0 +1,316 @@
+// Package cpumodels ships a curated table of named x86 CPU model profiles,
+// mirroring QEMU's "-cpu <model>" catalog, and answers "what is the closest
+// well-known baseline my host satisfies?" for live-migration compatibility
+// checks and container/VM scheduling.
+package cpumodels
+
+// Feature is one named capability a Model requires, tagged with the vendor
+// it's specific to ("amd", "intel", or "common" for both) and the group it
+// belongs to (e.g. "XSave", "Virtualization", "ExtendedSecurity",
+// "AdvancedMatrixExtensions"), which Eval uses to resolve group-qualified
+// names like "ExtendedSecurity.SEV".
+type Feature struct {
+	Name   string
+	Vendor string
+	Group  string
+}
+
+// Model is a named CPU profile: the vendor it applies to, optional
+// family/model/stepping hints identifying real silicon that satisfies it,
+// and the set of Features it requires.
+type Model struct {
+	Name       string
+	Vendor     string
+	Family     uint32
+	ModelID    uint32
+	Stepping   uint32
+	Generation int // higher sorts as newer within Vendor's lineage
+	Required   []Feature
+}
+
+// CPUInfo is the detected-feature input Match compares Models against.
+type CPUInfo struct {
+	Vendor   string
+	Features map[string]bool
+}
+
+// Models is the curated table of named profiles. It is not exhaustive --
+// it covers enough of the Intel client/server and AMD EPYC lineage to
+// demonstrate baseline matching, and is meant to grow the way QEMU's own
+// cpu_map does as new silicon ships. This is an abstract per-generation
+// feature floor, not a real chip's CPUID dump; for a full per-SKU leaf
+// replay (e.g. to decode every feature a specific chip reports), see the
+// cpuid package's own modelSnapshots table and its LoadModel function.
+// Where a modelSnapshots entry replays a SKU one of these Models describes,
+// the two tables use the same Name.
+var Models = []Model{
+	{
+		Name: "Nehalem", Vendor: "intel", Family: 6, ModelID: 26, Generation: 1,
+		Required: []Feature{
+			{Name: "SSE4.2", Vendor: "common", Group: "Baseline"},
+			{Name: "XSAVE_LEGACY", Vendor: "intel", Group: "XSave"},
+			{Name: "PMC_ARCH_V1", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Westmere", Vendor: "intel", Family: 6, ModelID: 44, Generation: 2,
+		Required: []Feature{
+			{Name: "SSE4.2", Vendor: "common", Group: "Baseline"},
+			{Name: "PCLMULQDQ", Vendor: "common", Group: "Baseline"},
+			{Name: "AES", Vendor: "common", Group: "Baseline"},
+			{Name: "PMC_ARCH_V1", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "SandyBridge", Vendor: "intel", Family: 6, ModelID: 42, Generation: 3,
+		Required: []Feature{
+			{Name: "AVX", Vendor: "common", Group: "Baseline"},
+			{Name: "XSAVE", Vendor: "common", Group: "XSave"},
+			{Name: "PMC_ARCH_V2", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Haswell", Vendor: "intel", Family: 6, ModelID: 60, Generation: 4,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "BMI1", Vendor: "common", Group: "Baseline"},
+			{Name: "BMI2", Vendor: "common", Group: "Baseline"},
+			{Name: "VMX_EPT", Vendor: "intel", Group: "Virtualization"},
+			{Name: "PMC_ARCH_V3", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Broadwell", Vendor: "intel", Family: 6, ModelID: 61, Generation: 5,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "ADX", Vendor: "common", Group: "Baseline"},
+			{Name: "RDSEED", Vendor: "common", Group: "Baseline"},
+			{Name: "VMX_EPT", Vendor: "intel", Group: "Virtualization"},
+			{Name: "PMC_ARCH_V3", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Skylake-Client-v1", Vendor: "intel", Family: 6, ModelID: 94, Generation: 6,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "XSAVEC", Vendor: "common", Group: "XSave"},
+			{Name: "VMX_EPT", Vendor: "intel", Group: "Virtualization"},
+			{Name: "PMC_ARCH_V4", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Skylake-Client-v2", Vendor: "intel", Family: 6, ModelID: 94, Generation: 6,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "XSAVEC", Vendor: "common", Group: "XSave"},
+			{Name: "VMX_EPT", Vendor: "intel", Group: "Virtualization"},
+			{Name: "IBRS", Vendor: "common", Group: "Mitigations"},
+			{Name: "PMC_ARCH_V4", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Skylake-Client-v3", Vendor: "intel", Family: 6, ModelID: 94, Generation: 6,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "XSAVEC", Vendor: "common", Group: "XSave"},
+			{Name: "VMX_EPT", Vendor: "intel", Group: "Virtualization"},
+			{Name: "VMX_VPID", Vendor: "intel", Group: "Virtualization"},
+			{Name: "IBRS", Vendor: "common", Group: "Mitigations"},
+			{Name: "PMC_ARCH_V4", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Skylake-Server", Vendor: "intel", Family: 6, ModelID: 85, Generation: 7,
+		Required: []Feature{
+			{Name: "AVX512F", Vendor: "intel", Group: "Baseline"},
+			{Name: "XSAVEC", Vendor: "common", Group: "XSave"},
+			{Name: "VMX_EPT", Vendor: "intel", Group: "Virtualization"},
+			{Name: "PMC_ARCH_V4", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Cascadelake-Server", Vendor: "intel", Family: 6, ModelID: 85, Stepping: 6, Generation: 8,
+		Required: []Feature{
+			{Name: "AVX512F", Vendor: "intel", Group: "Baseline"},
+			{Name: "AVX512_VNNI", Vendor: "intel", Group: "Baseline"},
+			{Name: "XSAVEC", Vendor: "common", Group: "XSave"},
+			{Name: "PMC_ARCH_V4", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Cooperlake", Vendor: "intel", Family: 6, ModelID: 85, Stepping: 11, Generation: 8,
+		Required: []Feature{
+			{Name: "AVX512F", Vendor: "intel", Group: "Baseline"},
+			{Name: "AVX512_VNNI", Vendor: "intel", Group: "Baseline"},
+			{Name: "AVX512_BF16", Vendor: "intel", Group: "Baseline"},
+			{Name: "XSAVEC", Vendor: "common", Group: "XSave"},
+			{Name: "PMC_ARCH_V4", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Icelake-Server", Vendor: "intel", Family: 6, ModelID: 106, Generation: 9,
+		Required: []Feature{
+			{Name: "AVX512_VNNI", Vendor: "intel", Group: "Baseline"},
+			{Name: "AVX512_VBMI2", Vendor: "intel", Group: "Baseline"},
+			{Name: "XSAVES", Vendor: "common", Group: "XSave"},
+			{Name: "PMC_ARCH_V5", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "SapphireRapids", Vendor: "intel", Family: 6, ModelID: 143, Generation: 10,
+		Required: []Feature{
+			{Name: "AMX_TILE", Vendor: "intel", Group: "AdvancedMatrixExtensions"},
+			{Name: "AMX_BF16", Vendor: "intel", Group: "AdvancedMatrixExtensions"},
+			{Name: "AMX_INT8", Vendor: "intel", Group: "AdvancedMatrixExtensions"},
+			{Name: "XSAVES", Vendor: "common", Group: "XSave"},
+			{Name: "PMC_ARCH_V5", Vendor: "intel", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "Bulldozer", Vendor: "amd", Family: 21, ModelID: 1, Generation: 0,
+		Required: []Feature{
+			{Name: "AVX", Vendor: "common", Group: "Baseline"},
+			{Name: "SVM", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SVM_NPT", Vendor: "amd", Group: "Virtualization"},
+		},
+	},
+	{
+		Name: "EPYC", Vendor: "amd", Family: 23, ModelID: 1, Generation: 1,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "SVM", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SVM_NPT", Vendor: "amd", Group: "Virtualization"},
+			{Name: "IBS", Vendor: "amd", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "EPYC-Rome", Vendor: "amd", Family: 23, ModelID: 49, Generation: 2,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "SVM", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SVM_NPT", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SEV", Vendor: "amd", Group: "ExtendedSecurity"},
+			{Name: "IBS", Vendor: "amd", Group: "PerfMon"},
+			{Name: "IBS_FETCH_CTL_EXTD", Vendor: "amd", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "EPYC-Milan", Vendor: "amd", Family: 25, ModelID: 1, Generation: 3,
+		Required: []Feature{
+			{Name: "AVX2", Vendor: "common", Group: "Baseline"},
+			{Name: "SVM", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SVM_NPT", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SEV", Vendor: "amd", Group: "ExtendedSecurity"},
+			{Name: "SEV_ES", Vendor: "amd", Group: "ExtendedSecurity"},
+			{Name: "IBS", Vendor: "amd", Group: "PerfMon"},
+			{Name: "IBS_OPFUSE", Vendor: "amd", Group: "PerfMon"},
+		},
+	},
+	{
+		Name: "EPYC-Genoa", Vendor: "amd", Family: 25, ModelID: 17, Generation: 4,
+		Required: []Feature{
+			{Name: "AVX512F", Vendor: "amd", Group: "Baseline"},
+			{Name: "SVM", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SVM_NPT", Vendor: "amd", Group: "Virtualization"},
+			{Name: "SEV", Vendor: "amd", Group: "ExtendedSecurity"},
+			{Name: "SEV_SNP", Vendor: "amd", Group: "ExtendedSecurity"},
+			{Name: "IBS", Vendor: "amd", Group: "PerfMon"},
+			{Name: "IBS_OPFUSE", Vendor: "amd", Group: "PerfMon"},
+		},
+	},
+}
+
+// Match finds the highest-Generation Model (restricted to the CPUInfo's
+// vendor, since profiles don't cross Intel/AMD lines) whose Required
+// features are fully satisfied by info.Features. If no Model matches
+// completely, it returns the closest Model -- the one missing the fewest
+// required features -- along with what it's missing.
+//
+// extras lists every detected feature not required by bestModel, so callers
+// can see what headroom the host has beyond the matched baseline.
+func Match(info CPUInfo) (bestModel string, missing []Feature, extras []Feature) {
+	var best *Model
+	var bestMissing []Feature
+
+	for i := range Models {
+		m := &Models[i]
+		if m.Vendor != info.Vendor {
+			continue
+		}
+
+		var mMissing []Feature
+		for _, req := range m.Required {
+			if !info.Features[req.Name] {
+				mMissing = append(mMissing, req)
+			}
+		}
+
+		switch {
+		case best == nil:
+			best, bestMissing = m, mMissing
+		case len(mMissing) < len(bestMissing):
+			best, bestMissing = m, mMissing
+		case len(mMissing) == len(bestMissing) && m.Generation > best.Generation:
+			best, bestMissing = m, mMissing
+		}
+	}
+
+	if best == nil {
+		return "", nil, nil
+	}
+
+	required := make(map[string]bool, len(best.Required))
+	for _, req := range best.Required {
+		required[req.Name] = true
+	}
+	for name, present := range info.Features {
+		if present && !required[name] {
+			extras = append(extras, Feature{Name: name, Vendor: info.Vendor})
+		}
+	}
+
+	return best.Name, bestMissing, extras
+}
+
+// Diff returns the symmetric difference between two named Models' Required
+// feature sets: every Feature required by exactly one of a or b. An empty
+// result means a and b require identical feature sets.
+func Diff(a, b string) []Feature {
+	modelA := findModel(a)
+	modelB := findModel(b)
+	if modelA == nil || modelB == nil {
+		return nil
+	}
+
+	inA := make(map[string]Feature, len(modelA.Required))
+	for _, f := range modelA.Required {
+		inA[f.Name] = f
+	}
+	inB := make(map[string]Feature, len(modelB.Required))
+	for _, f := range modelB.Required {
+		inB[f.Name] = f
+	}
+
+	var diff []Feature
+	for name, f := range inA {
+		if _, ok := inB[name]; !ok {
+			diff = append(diff, f)
+		}
+	}
+	for name, f := range inB {
+		if _, ok := inA[name]; !ok {
+			diff = append(diff, f)
+		}
+	}
+	return diff
+}
+
+// findModel looks up a Model by name, or returns nil if unknown.
+func findModel(name string) *Model {
+	for i := range Models {
+		if Models[i].Name == name {
+			return &Models[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package cpumodels
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FeatureSet is the detected-capability input Eval evaluates expressions
+// against: which named features are present, grouped by their Group for
+// qualified lookups like "ExtendedSecurity.SEV", plus an optional raw
+// register reader for numeric CPUID predicates like "CPUID.7:EDX[13]==1".
+type FeatureSet struct {
+	Vendor  string
+	Present map[string]bool
+
+	// ReadRegister resolves a numeric CPUID predicate's (leaf, subleaf,
+	// register) to its raw 32-bit value. Register is 0=EAX, 1=EBX, 2=ECX,
+	// 3=EDX. A nil ReadRegister makes any "CPUID...." predicate an error.
+	ReadRegister func(leaf, subleaf uint32, register int) (uint32, error)
+}
+
+// groupOf looks up the Group tag of a named feature by scanning Models'
+// Required lists, since that's the only feature catalog this package owns.
+// The first match wins; features aren't expected to carry conflicting
+// groups across models.
+func groupOf(name string) string {
+	for _, m := range Models {
+		for _, f := range m.Required {
+			if f.Name == name {
+				return f.Group
+			}
+		}
+	}
+	return ""
+}
+
+// has reports whether name is present, optionally restricted to group (for
+// "Group.Name" qualified lookups) -- "" group means unqualified.
+func (fs FeatureSet) has(group, name string) bool {
+	if !fs.Present[name] {
+		return false
+	}
+	if group == "" {
+		return true
+	}
+	return groupOf(name) == group
+}
+
+var cpuidPredicate = regexp.MustCompile(`^CPUID\.(0[xX][0-9a-fA-F]+|\d+)(?:\.(\d+))?:(EAX|EBX|ECX|EDX)\[(\d+)\]==([01])$`)
+
+var registerIndex = map[string]int{"EAX": 0, "EBX": 1, "ECX": 2, "EDX": 3}
+
+// predicate is a compiled, already-evaluable leaf of the expression tree.
+type predicate func(FeatureSet) (bool, error)
+
+// Eval parses and evaluates a small boolean expression over a FeatureSet's
+// detected features. Supported syntax:
+//
+//	AVX512_STATE & (AMX_TILE | AMX_BF16) & !SEV_SNP   -- &, |, !, parens
+//	ExtendedSecurity.SEV                               -- group-qualified name
+//	vendor==amd                                        -- vendor guard
+//	CPUID.7:EDX[13]==1                                 -- raw CPUID.leaf[.subleaf]:REG[bit]==0|1
+//
+// It mirrors the predicates QEMU evaluates when gating guest CPU
+// configuration on host feature masks.
+func Eval(expr string, detected FeatureSet) (bool, error) {
+	toks := tokenize(expr)
+	if len(toks) == 0 {
+		return false, fmt.Errorf("cpumodels: empty expression")
+	}
+
+	p := &parser{toks: toks}
+	pred, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.pos != len(p.toks) {
+		return false, fmt.Errorf("cpumodels: unexpected token %q", p.toks[p.pos])
+	}
+
+	return pred(detected)
+}
+
+// tokenize splits expr into operator/paren tokens and otherwise-contiguous
+// predicate tokens (identifiers, "vendor==amd", "CPUID.7:EDX[13]==1"), which
+// contain no whitespace of their own.
+func tokenize(expr string) []string {
+	var toks []string
+	i := 0
+	for i < len(expr) {
+		switch c := expr[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')' || c == '&' || c == '|' || c == '!':
+			toks = append(toks, string(c))
+			i++
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\n()&|!", rune(expr[j])) {
+				j++
+			}
+			toks = append(toks, expr[i:j])
+			i = j
+		}
+	}
+	return toks
+}
+
+type parser struct {
+	toks []string
+	pos  int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "|" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(fs FeatureSet) (bool, error) {
+			lv, err := l(fs)
+			if err != nil {
+				return false, err
+			}
+			if lv {
+				return true, nil
+			}
+			return r(fs)
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(fs FeatureSet) (bool, error) {
+			lv, err := l(fs)
+			if err != nil {
+				return false, err
+			}
+			if !lv {
+				return false, nil
+			}
+			return r(fs)
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (predicate, error) {
+	if p.peek() == "!" {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(fs FeatureSet) (bool, error) {
+			v, err := inner(fs)
+			if err != nil {
+				return false, err
+			}
+			return !v, nil
+		}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *parser) parseAtom() (predicate, error) {
+	tok := p.peek()
+	if tok == "(" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("cpumodels: expected ')'")
+		}
+		p.next()
+		return inner, nil
+	}
+	if tok == "" {
+		return nil, fmt.Errorf("cpumodels: unexpected end of expression")
+	}
+	p.next()
+	return compilePredicate(tok)
+}
+
+// compilePredicate turns one atomic token into a predicate: a vendor guard,
+// a raw CPUID bit test, or a (possibly group-qualified) feature name.
+func compilePredicate(tok string) (predicate, error) {
+	if strings.HasPrefix(tok, "vendor==") {
+		want := strings.TrimPrefix(tok, "vendor==")
+		return func(fs FeatureSet) (bool, error) {
+			return fs.Vendor == want, nil
+		}, nil
+	}
+
+	if strings.HasPrefix(tok, "CPUID.") {
+		m := cpuidPredicate.FindStringSubmatch(tok)
+		if m == nil {
+			return nil, fmt.Errorf("cpumodels: malformed CPUID predicate %q", tok)
+		}
+		leaf, err := strconv.ParseUint(m[1], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("cpumodels: malformed CPUID leaf in %q: %w", tok, err)
+		}
+		var subleaf uint64
+		if m[2] != "" {
+			subleaf, err = strconv.ParseUint(m[2], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("cpumodels: malformed CPUID subleaf in %q: %w", tok, err)
+			}
+		}
+		register := registerIndex[m[3]]
+		bit, _ := strconv.Atoi(m[4])
+		want := m[5] == "1"
+
+		return func(fs FeatureSet) (bool, error) {
+			if fs.ReadRegister == nil {
+				return false, fmt.Errorf("cpumodels: %q requires a FeatureSet with ReadRegister set", tok)
+			}
+			value, err := fs.ReadRegister(uint32(leaf), uint32(subleaf), register)
+			if err != nil {
+				return false, err
+			}
+			return ((value>>uint(bit))&1 == 1) == want, nil
+		}, nil
+	}
+
+	if group, name, ok := strings.Cut(tok, "."); ok {
+		return func(fs FeatureSet) (bool, error) {
+			return fs.has(group, name), nil
+		}, nil
+	}
+
+	return func(fs FeatureSet) (bool, error) {
+		return fs.has("", tok), nil
+	}, nil
+}
@@ -0,0 +1,74 @@
+package cpumodels
+
+import "sort"
+
+// MatchModel is Match's FeatureSet-based sibling: it takes the same
+// Vendor/Present-keyed detected-feature input Eval consumes instead of the
+// bare CPUInfo map, and returns plain feature-name slices rather than
+// Feature values -- the shape a "is this host safe to migrate a Cascadelake
+// guest onto?" caller wants without re-deriving CPUInfo by hand.
+func MatchModel(fs FeatureSet) (name string, added, missing []string) {
+	bestName, missingFeatures, extraFeatures := Match(CPUInfo{Vendor: fs.Vendor, Features: fs.Present})
+
+	for _, f := range missingFeatures {
+		missing = append(missing, f.Name)
+	}
+	for _, f := range extraFeatures {
+		added = append(added, f.Name)
+	}
+	return bestName, added, missing
+}
+
+// Baseline computes the greatest-common-subset FeatureSet across the named
+// models' Required features -- the safe floor a live-migration target has to
+// satisfy for every guest configured against any of models. Present holds a
+// feature only if every named model requires it; an unknown model name is
+// skipped. Vendor is set only when every named model shares one vendor,
+// since a baseline spanning Intel and AMD models has no single vendor.
+func Baseline(models ...string) FeatureSet {
+	var found []*Model
+	for _, name := range models {
+		if m := findModel(name); m != nil {
+			found = append(found, m)
+		}
+	}
+
+	fs := FeatureSet{Present: make(map[string]bool)}
+	if len(found) == 0 {
+		return fs
+	}
+
+	vendor := found[0].Vendor
+	for _, m := range found {
+		if m.Vendor != vendor {
+			vendor = ""
+			break
+		}
+	}
+	fs.Vendor = vendor
+
+	counts := make(map[string]int)
+	for _, m := range found {
+		seen := make(map[string]bool, len(m.Required))
+		for _, f := range m.Required {
+			seen[f.Name] = true
+		}
+		for name := range seen {
+			counts[name]++
+		}
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if counts[name] == len(found) {
+			fs.Present[name] = true
+		}
+	}
+
+	return fs
+}
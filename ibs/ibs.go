@@ -0,0 +1,126 @@
+// Package ibs decodes AMD's Instruction-Based Sampling capability leaf
+// (CPUID.8000001BH) and the related Northbridge/L3/Data-Fabric performance
+// counter extension bits, the way Linux's arch/x86/include/asm/amd-ibs.h
+// and perf_event_msr.c interpret the same register layout to decide which
+// perf_event PMU a host should register.
+package ibs
+
+import "github.com/earentir/cpuid"
+
+// IBS capability bits within CPUID.8000001BH:EAX, named after the
+// IBS_CAPS_* constants in Linux's amd-ibs.h.
+const (
+	capAvail         = 1 << 0
+	capFetchSam      = 1 << 1
+	capOpSam         = 1 << 2
+	capRdWrOpCnt     = 1 << 3
+	capOpCnt         = 1 << 4
+	capBrnTrgt       = 1 << 5
+	capOpCntExt      = 1 << 6
+	capRipInvalidChk = 1 << 7
+	capOpBrnFuse     = 1 << 8
+	capFetchCtlExtd  = 1 << 9
+	capOpDataCfgExtd = 1 << 10
+	capZen4          = 1 << 11
+)
+
+// IBSCapabilities is the decoded result of CPUID.8000001BH:EAX: which IBS
+// fetch/op-sampling features this CPU supports, plus the derived counter
+// widths a caller needs to program the corresponding IBS MSRs.
+type IBSCapabilities struct {
+	Available            bool // IBS is implemented at all
+	FetchSampling        bool // IC_IBS_EXTD_CTL / fetch sampling supported
+	OpSampling           bool // IBS execution (op) sampling supported
+	ReadWriteOpCounter   bool // separate current/max op counters (IbsOpCurCnt/IbsOpMaxCnt)
+	OpCounting           bool // op-sampling counts dispatched ops, not just cycles
+	BranchTarget         bool // branch target address capture (IbsBrTarget)
+	OpCounterExtended    bool // IbsOpCntExt: op counter widened from 20 to 27 bits
+	RIPInvalidCheck      bool // IbsRipInvalidChk: flags RIP as invalid on certain fetch errors
+	OpBranchFusion       bool // IbsOpBrnFuse: fused branch op reporting
+	FetchControlExtended bool // IbsFetchCtlExtd: extended fetch control register fields
+	OpDataExtended       bool // IbsOpDataCfgExtd: extended op data configuration (IBS OpData4)
+	Zen4Extensions       bool // Zen4-era IBS extensions (e.g. L3 miss filtering)
+	// OpCounterWidthBits is the width of IbsOpCurCnt/IbsOpMaxCnt: 27 bits
+	// when OpCounterExtended is set, 20 bits otherwise.
+	OpCounterWidthBits uint8
+}
+
+// GetIBSCapabilities decodes CPUID.8000001BH:EAX from src. It returns the
+// zero value (Available == false) on AMD CPUs that don't implement IBS and
+// on any non-AMD CPU, since the leaf is AMD-specific and undefined
+// elsewhere.
+func GetIBSCapabilities(src cpuid.Source) IBSCapabilities {
+	a, _, _, _ := src.CPUID(0x8000001B, 0)
+	if a&capAvail == 0 {
+		return IBSCapabilities{}
+	}
+
+	caps := IBSCapabilities{
+		Available:            true,
+		FetchSampling:        a&capFetchSam != 0,
+		OpSampling:           a&capOpSam != 0,
+		ReadWriteOpCounter:   a&capRdWrOpCnt != 0,
+		OpCounting:           a&capOpCnt != 0,
+		BranchTarget:         a&capBrnTrgt != 0,
+		OpCounterExtended:    a&capOpCntExt != 0,
+		RIPInvalidCheck:      a&capRipInvalidChk != 0,
+		OpBranchFusion:       a&capOpBrnFuse != 0,
+		FetchControlExtended: a&capFetchCtlExtd != 0,
+		OpDataExtended:       a&capOpDataCfgExtd != 0,
+		Zen4Extensions:       a&capZen4 != 0,
+	}
+
+	caps.OpCounterWidthBits = 20
+	if caps.OpCounterExtended {
+		caps.OpCounterWidthBits = 27
+	}
+
+	return caps
+}
+
+// NBCounterCapabilities is the decoded result of the Northbridge/L3/Data-
+// Fabric performance-counter extension bits in CPUID.80000001H:ECX.
+type NBCounterCapabilities struct {
+	CorePerfCtrExt bool // ECX[23] PerfCtrExtCore: core performance-counter extensions
+	DFPerfCtrExt   bool // ECX[24] PerfCtrExtDF: Data Fabric performance-counter extensions (formerly NB)
+	PerfTSC        bool // ECX[28] PerfTsc: performance time-stamp counter
+	L3PerfCtrExt   bool // ECX[29] PerfCtrExtLLC: L3 performance-counter extensions
+}
+
+// GetNBCounterCapabilities decodes the Northbridge/Data-Fabric/L3
+// performance-counter extension bits from CPUID.80000001H:ECX on src.
+func GetNBCounterCapabilities(src cpuid.Source) NBCounterCapabilities {
+	_, _, c, _ := src.CPUID(0x80000001, 0)
+	return NBCounterCapabilities{
+		CorePerfCtrExt: c&(1<<23) != 0,
+		DFPerfCtrExt:   c&(1<<24) != 0,
+		PerfTSC:        c&(1<<28) != 0,
+		L3PerfCtrExt:   c&(1<<29) != 0,
+	}
+}
+
+// Describe returns a summary of the perf_event PMU names a Linux host would
+// register for src's IBS and Northbridge/Data-Fabric/L3 counter support,
+// mirroring the "ibs_fetch"/"ibs_op"/"amd_df"/"amd_l3" PMU names
+// perf_event_msr.c and the amd_uncore driver expose.
+func Describe(src cpuid.Source) []string {
+	var pmus []string
+
+	ibsCaps := GetIBSCapabilities(src)
+	if ibsCaps.FetchSampling {
+		pmus = append(pmus, "ibs_fetch")
+	}
+	if ibsCaps.OpSampling {
+		pmus = append(pmus, "ibs_op")
+	}
+
+	nb := GetNBCounterCapabilities(src)
+	if nb.DFPerfCtrExt {
+		pmus = append(pmus, "amd_df")
+	}
+	if nb.L3PerfCtrExt {
+		pmus = append(pmus, "amd_l3")
+	}
+
+	return pmus
+}
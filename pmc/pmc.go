@@ -0,0 +1,114 @@
+// Package pmc turns the CPUID feature tables in github.com/earentir/cpuid
+// into an actionable performance-monitoring-counter API, the way NetBSD's
+// tprof arch backend maps CPUID/CPUID-derived MSR availability onto
+// concrete counters for Intel and AMD. It enumerates the fixed and general
+// counters CPUID leaf 0xA advertises (Intel architectural perfmon) and the
+// AMD core-PMC/IBS/northbridge counters listed in amdEvents, each gated on
+// its RequiredFeature name, then opens them via perf_event_open on Linux.
+package pmc
+
+import "github.com/earentir/cpuid"
+
+// PerfMonInfo is the decoded result of CPUID leaf 0xA (Intel Architectural
+// Performance Monitoring Leaf): how many fixed and general-purpose
+// counters the CPU exposes and how wide they are.
+type PerfMonInfo struct {
+	Version             uint8
+	NumGeneralCounters  uint8
+	GeneralCounterWidth uint8
+	NumFixedCounters    uint8
+	FixedCounterWidth   uint8
+}
+
+// GetPerfMonInfo decodes CPUID leaf 0xA from src. On AMD, where leaf 0xA is
+// architecturally reserved, Version is 0 and counter enumeration instead
+// goes through Events' AMD-specific feature gates.
+func GetPerfMonInfo(src cpuid.Source) PerfMonInfo {
+	a, _, _, d := src.CPUID(0xA, 0)
+	return PerfMonInfo{
+		Version:             uint8(a),
+		NumGeneralCounters:  uint8(a >> 8),
+		GeneralCounterWidth: uint8(a >> 16),
+		NumFixedCounters:    uint8(d & 0x1F),
+		FixedCounterWidth:   uint8((d >> 5) & 0xFF),
+	}
+}
+
+// Event is one countable hardware event, gated behind the CPUID feature
+// that has to be present before Open can reasonably succeed.
+type Event struct {
+	Name string
+	// Description is a short human-readable summary of what the event
+	// counts.
+	Description string
+	// CPUIDRef is the exact CPUID leaf/register/bit reference the gating
+	// feature lives at, e.g. "CPUID.80000007H:EDX.NB_PERF[bit 10]" --
+	// reported verbatim in the error Open returns when the bit is absent.
+	CPUIDRef string
+	// RequiredFeature is the canonical cpuid Feature name that must be
+	// supported for this event to be usable.
+	RequiredFeature string
+	// Vendor restricts the event to "intel", "amd", or "common" silicon.
+	Vendor string
+}
+
+// intelEvents are Intel architectural-perfmon (leaf 0xA) events. They're
+// gated on the perfmon leaf being present at all rather than on a single
+// named feature, since leaf 0xA's version field -- not a cpuFeaturesList
+// bit -- is what determines availability.
+var intelEvents = []Event{
+	{Name: "cpu-cycles", Description: "Unhalted core cycles", CPUIDRef: "CPUID.0AH:EAX[bits 7:0] >= 1", RequiredFeature: "", Vendor: "intel"},
+	{Name: "instructions", Description: "Instructions retired", CPUIDRef: "CPUID.0AH:EAX[bits 7:0] >= 1", RequiredFeature: "", Vendor: "intel"},
+	{Name: "llc-misses", Description: "Last-level cache misses", CPUIDRef: "CPUID.0AH:EAX[bits 7:0] >= 2", RequiredFeature: "", Vendor: "intel"},
+}
+
+// amdEvents are AMD events gated behind the RequiredFeature names below.
+// None of these names is registered in the cpuid package's feature tables,
+// so IsFeatureSupportedFromSource reports each one unsupported: Events
+// lists none of them and Open rejects them.
+var amdEvents = []Event{
+	{
+		Name: "nb-perf", Description: "Northbridge performance counters",
+		CPUIDRef: "CPUID.80000007H:EDX.NB_PERF[bit 10]", RequiredFeature: "NB_PERF", Vendor: "amd",
+	},
+	{
+		Name: "l3-perfctr", Description: "L3 cache performance counter extensions",
+		CPUIDRef: "CPUID.80000007H:EDX.L3_PERFCTR[bit 11]", RequiredFeature: "L3_PERFCTR", Vendor: "amd",
+	},
+	{
+		Name: "ibs-fetch", Description: "IBS instruction-fetch sampling",
+		CPUIDRef: "CPUID.80000008H:EBX.IBS_FETCH_CTL_MSR[bit 12]", RequiredFeature: "IBS_FETCH_CTL_MSR", Vendor: "amd",
+	},
+	{
+		Name: "proc-feedback", Description: "Processor feedback interface (effective frequency)",
+		CPUIDRef: "CPUID.80000007H:EDX.PROC_FEEDBACK[bit 11]", RequiredFeature: "PROC_FEEDBACK", Vendor: "amd",
+	},
+}
+
+// Events returns every Event usable on src for the given vendor ("intel" or
+// "amd"), filtering out events whose RequiredFeature isn't actually
+// supported -- so a caller iterating Events() never has to special-case a
+// counter Open would just reject anyway.
+func Events(src cpuid.Source, vendor string) []Event {
+	var table []Event
+	switch vendor {
+	case "intel":
+		if GetPerfMonInfo(src).Version == 0 {
+			return nil
+		}
+		table = intelEvents
+	case "amd":
+		table = amdEvents
+	default:
+		return nil
+	}
+
+	var available []Event
+	for _, ev := range table {
+		if ev.RequiredFeature != "" && !cpuid.IsFeatureSupportedFromSource(ev.RequiredFeature, src) {
+			continue
+		}
+		available = append(available, ev)
+	}
+	return available
+}
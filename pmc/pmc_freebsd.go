@@ -0,0 +1,115 @@
+//go:build freebsd
+
+package pmc
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/earentir/cpuid"
+)
+
+// hwpmcAllocate mirrors the fields of FreeBSD's struct pmc_op_pmcallocate
+// (sys/pmc.h) that Open actually needs to allocate a simple counting mode
+// PMC through /dev/hwpmc.
+type hwpmcAllocate struct {
+	EventCode uint32
+	PMCID     int32
+	CPU       int32
+	Mode      int32
+	Flags     uint32
+	_pad      uint32
+	Reserved  [8]uint64
+}
+
+const (
+	hwpmcDevice = "/dev/hwpmc"
+
+	pmcModeSC = 0 // PMC_MODE_SC: system-wide counting mode
+
+	// ioctl command numbers below are the FreeBSD PMC_OP_* codes encoded
+	// via _IOWR('p', op, struct pmc_op_pmcallocate) -- reproduced here
+	// rather than imported, since this package has no cgo/x/sys dependency.
+	pmcOpAllocate = 0xc0205000 + 1
+	pmcOpStart    = 0xc0205000 + 3
+	pmcOpStop     = 0xc0205000 + 4
+	pmcOpRW       = 0xc0205000 + 5
+	pmcOpRelease  = 0xc0205000 + 2
+)
+
+// Counter is an open FreeBSD hwpmc(4) performance counter.
+type Counter struct {
+	dev *os.File
+	id  int32
+}
+
+// eventCode maps the curated Event names this package knows about to a
+// hwpmc(4) raw event code. Events without a known mapping fail Open with
+// the event's CPUIDRef rather than guessing at an encoding.
+func eventCode(ev Event) (uint32, bool) {
+	switch ev.Name {
+	case "cpu-cycles":
+		return 0x76, true // k8-bu-cpu-clk-unhalted-equivalent code, architecture-dependent
+	case "instructions":
+		return 0xc0, true // retired-instructions event code
+	case "nb-perf", "l3-perfctr", "ibs-fetch", "proc-feedback":
+		return 0, false // require AMD northbridge/IBS MSR access hwpmc doesn't expose uniformly
+	default:
+		return 0, false
+	}
+}
+
+// Open gates ev behind its RequiredFeature (see Events) and, if satisfied
+// and a known hwpmc(4) encoding exists, allocates a system-wide counting
+// mode PMC through /dev/hwpmc.
+func Open(src cpuid.Source, ev Event) (*Counter, error) {
+	if ev.RequiredFeature != "" && !cpuid.IsFeatureSupportedFromSource(ev.RequiredFeature, src) {
+		return nil, fmt.Errorf("pmc: %s unavailable: %s not supported (%s)", ev.Name, ev.RequiredFeature, ev.CPUIDRef)
+	}
+
+	code, ok := eventCode(ev)
+	if !ok {
+		return nil, fmt.Errorf("pmc: %s has no known hwpmc(4) encoding (see %s)", ev.Name, ev.CPUIDRef)
+	}
+
+	dev, err := os.OpenFile(hwpmcDevice, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("pmc: open %s: %w", hwpmcDevice, err)
+	}
+
+	alloc := hwpmcAllocate{EventCode: code, CPU: -1, Mode: pmcModeSC}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dev.Fd(), uintptr(pmcOpAllocate), uintptr(unsafe.Pointer(&alloc))); errno != 0 {
+		dev.Close()
+		return nil, fmt.Errorf("pmc: PMC_OP_PMCALLOCATE %s: %w", ev.Name, errno)
+	}
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dev.Fd(), uintptr(pmcOpStart), uintptr(unsafe.Pointer(&alloc.PMCID))); errno != 0 {
+		dev.Close()
+		return nil, fmt.Errorf("pmc: PMC_OP_PMCSTART %s: %w", ev.Name, errno)
+	}
+
+	return &Counter{dev: dev, id: alloc.PMCID}, nil
+}
+
+// Read returns the counter's current accumulated value.
+func (c *Counter) Read() (uint64, error) {
+	rw := struct {
+		PMCID int32
+		_pad  int32
+		Value uint64
+	}{PMCID: c.id}
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, c.dev.Fd(), uintptr(pmcOpRW), uintptr(unsafe.Pointer(&rw))); errno != 0 {
+		return 0, fmt.Errorf("pmc: PMC_OP_PMCRW: %w", errno)
+	}
+	return rw.Value, nil
+}
+
+// Close stops and releases the counter.
+func (c *Counter) Close() error {
+	syscall.Syscall(syscall.SYS_IOCTL, c.dev.Fd(), uintptr(pmcOpStop), uintptr(unsafe.Pointer(&c.id)))
+	syscall.Syscall(syscall.SYS_IOCTL, c.dev.Fd(), uintptr(pmcOpRelease), uintptr(unsafe.Pointer(&c.id)))
+	return c.dev.Close()
+}
@@ -0,0 +1,29 @@
+//go:build !linux && !freebsd
+
+package pmc
+
+import (
+	"fmt"
+
+	"github.com/earentir/cpuid"
+)
+
+// Counter is never constructed on this platform; Open always fails.
+type Counter struct{}
+
+// Open always fails on platforms this package doesn't have a
+// perf_event_open/hwpmc(4) backend for, reporting ev's CPUID reference so
+// the caller can at least tell what the event would have needed.
+func Open(src cpuid.Source, ev Event) (*Counter, error) {
+	return nil, fmt.Errorf("pmc: %s not supported on this platform (see %s)", ev.Name, ev.CPUIDRef)
+}
+
+// Read never succeeds; Counter is never non-nil on this platform.
+func (c *Counter) Read() (uint64, error) {
+	return 0, fmt.Errorf("pmc: counters not supported on this platform")
+}
+
+// Close is a no-op on this platform.
+func (c *Counter) Close() error {
+	return nil
+}
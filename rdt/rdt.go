@@ -0,0 +1,181 @@
+// Package rdt decodes the RDT/PQOS capability leaves from a
+// github.com/earentir/cpuid Source into an operational Resource Director Technology (Intel RDT) / Platform QoS
+// (AMD PQOS) API: enumerating CAT/CDP/MBA class-of-service widths from
+// CPUID leaf 0x10 (Intel) or 0x8000_0020 (AMD), and CMT/MBM RMIDs from
+// CPUID leaf 0xF -- then, on Linux, driving the resctrl filesystem to act
+// on what was enumerated.
+package rdt
+
+import (
+	"fmt"
+
+	"github.com/earentir/cpuid"
+)
+
+// CATInfo is one cache level's Cache Allocation Technology capacity, as
+// decoded from CPUID.10H sub-leaves 1 (L3) / 2 (L2) on Intel or
+// CPUID.8000_0020H sub-leaves 1 (L3) / 2 (L2) on AMD.
+type CATInfo struct {
+	Level        int // 2 or 3
+	MaskLength   uint8
+	CDPSupported bool
+	HighestCOS   uint16
+}
+
+// MBAInfo is Memory Bandwidth Allocation capacity, as decoded from
+// CPUID.10H sub-leaf 3 (Intel) or CPUID.8000_0020H sub-leaf 3 (AMD).
+type MBAInfo struct {
+	MaxDelay       uint16
+	LinearResponse bool
+	HighestCOS     uint16
+}
+
+// MonitoringInfo is Cache/Memory-Bandwidth Monitoring (CMT/MBM) capacity,
+// as decoded from CPUID.0FH sub-leaves 0/1.
+type MonitoringInfo struct {
+	MaxRMID              uint32
+	ConversionFactor     uint32
+	L3OccupancySupported bool
+	L3TotalBWSupported   bool
+	L3LocalBWSupported   bool
+}
+
+// GetCAT decodes the Cache Allocation Technology capacity for the given
+// cache level (2 or 3). ok is false if src's vendor/CPUID doesn't advertise
+// CAT for that level.
+func GetCAT(src cpuid.Source, level int) (info CATInfo, ok bool) {
+	if isAMD(src) {
+		return getAMDCAT(src, level)
+	}
+	return getIntelCAT(src, level)
+}
+
+// GetMBA decodes Memory Bandwidth Allocation capacity. ok is false if src
+// doesn't advertise MBA.
+func GetMBA(src cpuid.Source) (info MBAInfo, ok bool) {
+	if isAMD(src) {
+		return getAMDMBA(src)
+	}
+	return getIntelMBA(src)
+}
+
+// GetMonitoring decodes CMT/MBM capacity from CPUID.0FH. AMD has no
+// equivalent leaf, so ok is always false there -- AMD bandwidth monitoring
+// is exposed through performance-counter MSRs instead (see the pmc
+// package's nb-perf and l3-perfctr events), not a CPUID-enumerated RMID
+// space.
+func GetMonitoring(src cpuid.Source) (info MonitoringInfo, ok bool) {
+	if isAMD(src) {
+		return MonitoringInfo{}, false
+	}
+
+	_, _, _, edx0 := src.CPUID(0xF, 0)
+	if edx0&(1<<1) == 0 {
+		return MonitoringInfo{}, false
+	}
+
+	// EBX1's max-RMID-range field is for the deprecated QM_EVTSEL
+	// addressing scheme; ECX1's max-RMID is what current callers need.
+	eax1, _, ecx1, edx1 := src.CPUID(0xF, 1)
+	return MonitoringInfo{
+		MaxRMID:              ecx1,
+		ConversionFactor:     eax1,
+		L3OccupancySupported: edx1&(1<<0) != 0,
+		L3TotalBWSupported:   edx1&(1<<1) != 0,
+		L3LocalBWSupported:   edx1&(1<<2) != 0,
+	}, true
+}
+
+// isAMD reports whether src's vendor ID string is "AuthenticAMD", decoded
+// from CPUID.0H the same way GetVendorID does.
+func isAMD(src cpuid.Source) bool {
+	_, b, c, d := src.CPUID(0, 0)
+	buf := make([]byte, 0, 12)
+	for _, reg := range []uint32{b, d, c} {
+		buf = append(buf, byte(reg), byte(reg>>8), byte(reg>>16), byte(reg>>24))
+	}
+	return string(buf) == "AuthenticAMD"
+}
+
+// getIntelCAT decodes CPUID.10H sub-leaf 1 (L3) or 2 (L2).
+func getIntelCAT(src cpuid.Source, level int) (CATInfo, bool) {
+	subleaf, err := catSubleaf(level)
+	if err != nil {
+		return CATInfo{}, false
+	}
+
+	_, ebx0, _, _ := src.CPUID(0x10, 0)
+	if ebx0&(1<<uint(subleaf-1)) == 0 {
+		return CATInfo{}, false
+	}
+
+	eax, _, ecx, edx := src.CPUID(0x10, uint32(subleaf))
+	return CATInfo{
+		Level:        level,
+		MaskLength:   uint8(eax&0x1F) + 1,
+		CDPSupported: ecx&(1<<2) != 0,
+		HighestCOS:   uint16(edx & 0xFFFF),
+	}, true
+}
+
+// getIntelMBA decodes CPUID.10H sub-leaf 3.
+func getIntelMBA(src cpuid.Source) (MBAInfo, bool) {
+	_, ebx0, _, _ := src.CPUID(0x10, 0)
+	if ebx0&(1<<2) == 0 {
+		return MBAInfo{}, false
+	}
+
+	eax, _, ecx, edx := src.CPUID(0x10, 3)
+	return MBAInfo{
+		MaxDelay:       uint16(eax&0xFFF) + 1,
+		LinearResponse: ecx&(1<<2) != 0,
+		HighestCOS:     uint16(edx & 0xFFFF),
+	}, true
+}
+
+// getAMDCAT decodes CPUID.8000_0020H sub-leaf 1 (L3) or 2 (L2), which
+// mirrors Intel's CPUID.10H layout closely enough to share field offsets.
+func getAMDCAT(src cpuid.Source, level int) (CATInfo, bool) {
+	subleaf, err := catSubleaf(level)
+	if err != nil {
+		return CATInfo{}, false
+	}
+
+	_, ebx0, _, _ := src.CPUID(0x80000020, 0)
+	if ebx0&(1<<uint(subleaf-1)) == 0 {
+		return CATInfo{}, false
+	}
+
+	eax, _, ecx, edx := src.CPUID(0x80000020, uint32(subleaf))
+	return CATInfo{
+		Level:        level,
+		MaskLength:   uint8(eax&0x1F) + 1,
+		CDPSupported: ecx&(1<<0) != 0,
+		HighestCOS:   uint16(edx & 0xFFFF),
+	}, true
+}
+
+// getAMDMBA decodes CPUID.8000_0020H sub-leaf 3.
+func getAMDMBA(src cpuid.Source) (MBAInfo, bool) {
+	_, ebx0, _, _ := src.CPUID(0x80000020, 0)
+	if ebx0&(1<<1) == 0 {
+		return MBAInfo{}, false
+	}
+
+	eax, _, _, edx := src.CPUID(0x80000020, 3)
+	return MBAInfo{
+		MaxDelay:   uint16(eax&0xFFF) + 1,
+		HighestCOS: uint16(edx & 0xFFFF),
+	}, true
+}
+
+func catSubleaf(level int) (int, error) {
+	switch level {
+	case 2:
+		return 2, nil
+	case 3:
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("rdt: unsupported cache level %d (want 2 or 3)", level)
+	}
+}
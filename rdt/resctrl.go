@@ -0,0 +1,39 @@
+package rdt
+
+import "fmt"
+
+// resctrlRoot is the conventional mountpoint for Linux's resctrl
+// filesystem, which this package drives to turn the CAT/CDP/MBA/CMT/MBM
+// capacities GetCAT/GetMBA/GetMonitoring decode into an operational API.
+const resctrlRoot = "/sys/fs/resctrl"
+
+// Group is a resctrl control/monitoring group: a named allocation of
+// cache-ways/MBA delay values that a set of tasks or CPUs can be
+// associated with. The default (root) group is represented by an empty
+// Name.
+type Group struct {
+	Name string
+}
+
+// CacheSchema maps a cache domain ID (as resctrl numbers them, typically
+// one per L3/L2 instance) to the CBM (capacity bitmask) to program for
+// that domain.
+type CacheSchema map[int]uint32
+
+// MBASchema maps a memory-bandwidth domain ID to the MBA throttling value
+// to program for that domain -- a percentage of available bandwidth on
+// Intel, or a delay-based value on AMD, per MBAInfo.LinearResponse.
+type MBASchema map[int]uint16
+
+// MonitoringSample is one snapshot of a group's CMT/MBM counters, read
+// from its mon_data directory.
+type MonitoringSample struct {
+	L3Occupancy    uint64
+	TotalBandwidth uint64
+	LocalBandwidth uint64
+}
+
+// ErrNotSupported is returned by the resctrl operations on platforms
+// without a resctrl filesystem (anything but Linux) or when the host
+// kernel hasn't mounted one.
+var ErrNotSupported = fmt.Errorf("rdt: resctrl filesystem not available on this platform")
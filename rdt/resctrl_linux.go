@@ -0,0 +1,188 @@
+//go:build linux
+
+package rdt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IsAvailable reports whether the kernel has resctrl mounted at
+// /sys/fs/resctrl -- the precondition for every other function in this
+// file.
+func IsAvailable() bool {
+	_, err := os.Stat(filepath.Join(resctrlRoot, "schemata"))
+	return err == nil
+}
+
+// path returns g's directory under resctrlRoot; the default group (empty
+// Name) is the mountpoint itself.
+func (g Group) path() string {
+	if g.Name == "" {
+		return resctrlRoot
+	}
+	return filepath.Join(resctrlRoot, g.Name)
+}
+
+// CreateGroup makes a new resctrl control/monitoring group by mkdir'ing
+// it under resctrlRoot -- the kernel populates schemata/tasks/cpus/mon_data
+// as a side effect of the directory's creation.
+func CreateGroup(name string) (*Group, error) {
+	if !IsAvailable() {
+		return nil, ErrNotSupported
+	}
+	if err := os.Mkdir(filepath.Join(resctrlRoot, name), 0755); err != nil {
+		return nil, fmt.Errorf("rdt: create group %s: %w", name, err)
+	}
+	return &Group{Name: name}, nil
+}
+
+// Groups lists the control/monitoring groups that currently exist under
+// resctrlRoot, not including the default (root) group.
+func Groups() ([]string, error) {
+	if !IsAvailable() {
+		return nil, ErrNotSupported
+	}
+	entries, err := os.ReadDir(resctrlRoot)
+	if err != nil {
+		return nil, fmt.Errorf("rdt: list groups: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		switch e.Name() {
+		case "info", "mon_groups", "mon_data":
+			// resctrl's own metadata/monitoring directories, not groups.
+			continue
+		}
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Destroy removes g's directory, evicting any tasks/CPUs still assigned
+// to it back to the default group -- resctrl's own rmdir semantics.
+func (g *Group) Destroy() error {
+	if g.Name == "" {
+		return fmt.Errorf("rdt: cannot destroy the default group")
+	}
+	if err := os.Remove(g.path()); err != nil {
+		return fmt.Errorf("rdt: destroy group %s: %w", g.Name, err)
+	}
+	return nil
+}
+
+// AssignTask moves pid into g by writing it to g's tasks file, the same
+// mechanism the resctrl(7) "taskset"-like workflow documents.
+func (g *Group) AssignTask(pid int) error {
+	return os.WriteFile(filepath.Join(g.path(), "tasks"), []byte(strconv.Itoa(pid)), 0644)
+}
+
+// AssignCPU moves logical CPU cpu into g by writing its number to g's
+// cpus_list file, which replaces the CPU list g had before.
+func (g *Group) AssignCPU(cpu int) error {
+	return os.WriteFile(filepath.Join(g.path(), "cpus_list"), []byte(strconv.Itoa(cpu)), 0644)
+}
+
+// buildSchemataLine renders one resctrl schemata line, e.g.
+// "L3:0=ff;1=ff" or "MB:0=90;1=90", in ascending domain-ID order so
+// output is deterministic.
+func buildSchemataLine(resource string, domains map[int]uint64, hex bool) string {
+	ids := make([]int, 0, len(domains))
+	for id := range domains {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if hex {
+			parts = append(parts, fmt.Sprintf("%d=%x", id, domains[id]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d=%d", id, domains[id]))
+		}
+	}
+	return resource + ":" + strings.Join(parts, ";")
+}
+
+// SetCacheSchema programs g's CAT (or CDP, via resource "L3CODE"/"L3DATA"/
+// "L2CODE"/"L2DATA") cache-ways bitmasks by writing a schemata line built
+// from schema. resource is normally "L3" or "L2", matching CATInfo.Level.
+func (g *Group) SetCacheSchema(resource string, schema CacheSchema) error {
+	domains := make(map[int]uint64, len(schema))
+	for id, mask := range schema {
+		domains[id] = uint64(mask)
+	}
+	line := buildSchemataLine(resource, domains, true)
+	if err := os.WriteFile(filepath.Join(g.path(), "schemata"), []byte(line+"\n"), 0644); err != nil {
+		return fmt.Errorf("rdt: write %s schemata for group %q: %w", resource, g.Name, err)
+	}
+	return nil
+}
+
+// SetMBASchema programs g's Memory Bandwidth Allocation values by writing
+// an "MB:" schemata line built from schema.
+func (g *Group) SetMBASchema(schema MBASchema) error {
+	domains := make(map[int]uint64, len(schema))
+	for id, v := range schema {
+		domains[id] = uint64(v)
+	}
+	line := buildSchemataLine("MB", domains, false)
+	if err := os.WriteFile(filepath.Join(g.path(), "schemata"), []byte(line+"\n"), 0644); err != nil {
+		return fmt.Errorf("rdt: write MB schemata for group %q: %w", g.Name, err)
+	}
+	return nil
+}
+
+// Schemata returns the raw, kernel-reported contents of g's schemata
+// file -- one line per resource ("L3:...", "MB:...", etc), exactly as
+// resctrl(7) documents it.
+func (g *Group) Schemata() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(g.path(), "schemata"))
+	if err != nil {
+		return nil, fmt.Errorf("rdt: read schemata for group %q: %w", g.Name, err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n"), nil
+}
+
+// readMonFile reads one numeric counter file out of g's mon_data
+// directory for the given domain, e.g. mon_data/mon_L3_00/llc_occupancy.
+func (g *Group) readMonFile(domainDir, file string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(g.path(), "mon_data", domainDir, file))
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("rdt: parse %s/%s: %w", domainDir, file, err)
+	}
+	return v, nil
+}
+
+// Sample reads g's CMT (L3 occupancy) and MBM (total/local bandwidth)
+// counters for the given L3 monitoring domain, as resctrl names its
+// mon_data/mon_L3_<domain> subdirectories.
+func (g *Group) Sample(domain int) (MonitoringSample, error) {
+	dir := fmt.Sprintf("mon_L3_%02d", domain)
+
+	occ, err := g.readMonFile(dir, "llc_occupancy")
+	if err != nil {
+		return MonitoringSample{}, fmt.Errorf("rdt: sample group %q domain %d: %w", g.Name, domain, err)
+	}
+	total, err := g.readMonFile(dir, "mbm_total_bytes")
+	if err != nil {
+		return MonitoringSample{}, fmt.Errorf("rdt: sample group %q domain %d: %w", g.Name, domain, err)
+	}
+	local, err := g.readMonFile(dir, "mbm_local_bytes")
+	if err != nil {
+		return MonitoringSample{}, fmt.Errorf("rdt: sample group %q domain %d: %w", g.Name, domain, err)
+	}
+
+	return MonitoringSample{L3Occupancy: occ, TotalBandwidth: total, LocalBandwidth: local}, nil
+}
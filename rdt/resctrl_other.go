@@ -0,0 +1,54 @@
+//go:build !linux
+
+package rdt
+
+// IsAvailable always reports false on non-Linux platforms -- resctrl is a
+// Linux-specific filesystem with no equivalent elsewhere.
+func IsAvailable() bool {
+	return false
+}
+
+// CreateGroup always fails on this platform.
+func CreateGroup(name string) (*Group, error) {
+	return nil, ErrNotSupported
+}
+
+// Groups always fails on this platform.
+func Groups() ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// Destroy always fails on this platform.
+func (g *Group) Destroy() error {
+	return ErrNotSupported
+}
+
+// AssignTask always fails on this platform.
+func (g *Group) AssignTask(pid int) error {
+	return ErrNotSupported
+}
+
+// AssignCPU always fails on this platform.
+func (g *Group) AssignCPU(cpu int) error {
+	return ErrNotSupported
+}
+
+// SetCacheSchema always fails on this platform.
+func (g *Group) SetCacheSchema(resource string, schema CacheSchema) error {
+	return ErrNotSupported
+}
+
+// SetMBASchema always fails on this platform.
+func (g *Group) SetMBASchema(schema MBASchema) error {
+	return ErrNotSupported
+}
+
+// Schemata always fails on this platform.
+func (g *Group) Schemata() ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// Sample always fails on this platform.
+func (g *Group) Sample(domain int) (MonitoringSample, error) {
+	return MonitoringSample{}, ErrNotSupported
+}